@@ -5,7 +5,9 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	"github.com/spf13/pflag"
 
+	"github.com/deckhouse/dmt/internal/cache"
 	"github.com/deckhouse/dmt/internal/flags"
 	"github.com/deckhouse/dmt/internal/logger"
 	"github.com/deckhouse/dmt/internal/manager"
@@ -27,6 +29,9 @@ func main() {
 	gen := flags.InitGenFlagSet()
 	gen.AddFlagSet(defaults)
 
+	cacheFlags := flags.InitCacheFlagSet()
+	cacheFlags.AddFlagSet(defaults)
+
 	if len(os.Args) < 2 {
 		flags.GeneralParse(defaults)
 		defaults.Usage()
@@ -49,6 +54,16 @@ func main() {
 		runLint(dirs)
 	case "gen":
 		flags.GeneralParse(gen)
+	case "cache":
+		flags.GeneralParse(cacheFlags)
+
+		args := cacheFlags.Args()[1:]
+		if len(args) != 1 {
+			cacheFlags.Usage()
+			os.Exit(1)
+		}
+
+		runCache(args[0], cacheFlags)
 	default:
 		flags.GeneralParse(defaults)
 		defaults.Usage()
@@ -71,3 +86,30 @@ func runLint(dirs []string) {
 		os.Exit(1)
 	}
 }
+
+func runCache(command string, cacheFlags *pflag.FlagSet) {
+	c := cache.New(flags.CacheDir, Version)
+	if c == nil {
+		logger.CheckErr("cache dir is not set")
+	}
+
+	switch command {
+	case "clean":
+		logger.CheckErr(c.Clean())
+		fmt.Printf("Cache %s is cleaned\n", c.Dir())
+	case "status":
+		status, err := c.Status()
+		logger.CheckErr(err)
+
+		fmt.Printf("Cache dir: %s\n", c.Dir())
+		if len(status) == 0 {
+			fmt.Println("Cache is empty")
+		}
+		for _, s := range status {
+			fmt.Printf("%s: %d entries, %d bytes\n", s.Kind, s.Entries, s.Size)
+		}
+	default:
+		cacheFlags.Usage()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+)
+
+const (
+	// RendersKind contains rendered chart files
+	RendersKind = "renders"
+	// ResultsKind contains linter results
+	ResultsKind = "results"
+
+	entryExt = ".json"
+
+	// unreleasedVersion is the version of dmt built without release ldflags
+	unreleasedVersion = "HEAD"
+)
+
+// Cache is a content-addressed on-disk storage.
+// Entries are stored in <dir>/<kind>/<key[:2]>/<key>.json. A nil *Cache is a disabled cache.
+type Cache struct {
+	dir string
+	// version invalidates entries created by other dmt versions
+	version string
+}
+
+// DefaultDir returns the dmt directory in the user cache dir.
+// It returns an empty string if the user cache dir is not defined.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "dmt")
+}
+
+// New returns the cache stored in dir for the dmt version. Empty dir disables the cache.
+// Entries of unreleased builds are keyed by the build VCS revision or the executable hash,
+// they are not cached if neither is known.
+func New(dir, version string) *Cache {
+	if dir == "" {
+		return nil
+	}
+
+	if version == unreleasedVersion {
+		version = buildVersion()
+	}
+
+	return &Cache{dir: dir, version: version}
+}
+
+// buildVersion returns the VCS revision of the running dmt build.
+// The executable hash is returned for builds without a revision or with uncommitted changes.
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, modified string
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value
+			}
+		}
+		if revision != "" && modified != "true" {
+			return unreleasedVersion + "-" + revision
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+
+	f, err := os.Open(executable)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+
+	return unreleasedVersion + "-" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) Dir() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.dir
+}
+
+// Key returns the hash of JSON representation of the dmt version and parts.
+// It returns an empty key for a disabled cache or an unknown dmt version.
+func (c *Cache) Key(parts ...any) (string, error) {
+	if c == nil || c.version == "" {
+		return "", nil
+	}
+
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, part := range append([]any{c.version}, parts...) {
+		if err := enc.Encode(part); err != nil {
+			return "", fmt.Errorf("cache key: %w", err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get decodes the entry into v. It returns false if the entry does not exist or can't be decoded.
+func (c *Cache) Get(kind, key string, v any) bool {
+	if c == nil || key == "" {
+		return false
+	}
+
+	content, err := os.ReadFile(c.path(kind, key))
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(content, v) == nil
+}
+
+// Put stores v as the entry. The entry is written to a temporary file first,
+// so concurrent readers never see a partially written entry.
+func (c *Cache) Put(kind, key string, v any) error {
+	if c == nil || key == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache encode: %w", err)
+	}
+
+	path := c.path(kind, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cache write: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("cache write: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cache write: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cache write: %w", err)
+	}
+
+	return nil
+}
+
+// Clean removes all cache entries.
+func (c *Cache) Clean() error {
+	if c == nil {
+		return nil
+	}
+
+	return os.RemoveAll(c.dir)
+}
+
+// KindStatus contains the number and the total size of entries of a kind.
+type KindStatus struct {
+	Kind    string
+	Entries int
+	Size    int64
+}
+
+// Status returns cache usage for every kind.
+func (c *Cache) Status() ([]KindStatus, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	kinds := make(map[string]*KindStatus)
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != entryExt {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.dir, path)
+		if err != nil {
+			return err
+		}
+		kind := filepath.Dir(filepath.Dir(rel))
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if kinds[kind] == nil {
+			kinds[kind] = &KindStatus{Kind: kind}
+		}
+		kinds[kind].Entries++
+		kinds[kind].Size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cache status: %w", err)
+	}
+
+	result := make([]KindStatus, 0, len(kinds))
+	for _, status := range kinds {
+		result = append(result, *status)
+	}
+	slices.SortFunc(result, func(a, b KindStatus) int {
+		return cmp.Compare(a.Kind, b.Kind)
+	})
+
+	return result, nil
+}
+
+func (c *Cache) path(kind, key string) string {
+	prefix := key
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+
+	return filepath.Join(c.dir, kind, prefix, key+entryExt)
+}
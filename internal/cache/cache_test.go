@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	c := New(t.TempDir(), "v1")
+
+	key, err := c.Key("module", map[string]any{"replicas": 2})
+	require.NoError(t, err)
+
+	var files map[string]string
+	assert.False(t, c.Get(RendersKind, key, &files))
+
+	require.NoError(t, c.Put(RendersKind, key, map[string]string{"templates/a.yaml": "kind: Pod"}))
+	require.True(t, c.Get(RendersKind, key, &files))
+	assert.Equal(t, map[string]string{"templates/a.yaml": "kind: Pod"}, files)
+
+	status, err := c.Status()
+	require.NoError(t, err)
+	require.Len(t, status, 1)
+	assert.Equal(t, RendersKind, status[0].Kind)
+	assert.Equal(t, 1, status[0].Entries)
+
+	otherVersionKey, err := New(c.Dir(), "v2").Key("module", map[string]any{"replicas": 2})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherVersionKey)
+
+	require.NoError(t, c.Clean())
+	assert.False(t, c.Get(RendersKind, key, &files))
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := New("", "v1")
+
+	key, err := c.Key("module")
+	require.NoError(t, err)
+	require.NoError(t, c.Put(ResultsKind, key, "value"))
+
+	var value string
+	assert.False(t, c.Get(ResultsKind, key, &value))
+}
+
+func TestUnreleasedVersion(t *testing.T) {
+	c := New(t.TempDir(), unreleasedVersion)
+	assert.NotEqual(t, unreleasedVersion, c.version)
+	assert.Regexp(t, `^HEAD-[0-9a-f]+$`, c.version)
+
+	key, err := c.Key("module")
+	require.NoError(t, err)
+	assert.NotEmpty(t, key)
+}
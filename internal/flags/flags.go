@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/spf13/pflag"
+
+	"github.com/deckhouse/dmt/internal/cache"
 )
 
 const (
@@ -14,6 +16,9 @@ const (
 var (
 	LintersLimit int
 	LogLevel     string
+	CacheDir     string
+	// LibraryChartDirs are added to library chart dirs from the config
+	LibraryChartDirs []string
 )
 
 var (
@@ -28,7 +33,7 @@ func InitDefaultFlagSet() *pflag.FlagSet {
 	defaults.BoolVarP(&PrintVersion, "version", "v", false, "version message")
 
 	defaults.Usage = func() {
-		_, _ = fmt.Fprintln(os.Stderr, "Usage: dmt [gen|lint] [OPTIONS]")
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: dmt [gen|lint|cache] [OPTIONS]")
 		defaults.PrintDefaults()
 	}
 
@@ -40,6 +45,8 @@ func InitLintFlagSet() *pflag.FlagSet {
 
 	lint.IntVarP(&LintersLimit, "parallel", "p", numThreads, "number of threads for parallel processing")
 	lint.StringVarP(&LogLevel, "log-level", "l", "INFO", "log-level [DEBUG | INFO | WARN | ERROR]")
+	lint.StringVar(&CacheDir, "cache-dir", cache.DefaultDir(), "directory to cache renders and linter results in, empty value disables the cache")
+	lint.StringSliceVar(&LibraryChartDirs, "library-chart-dir", nil, "library chart or directory with library charts to add to modules not vendoring them, can be repeated")
 
 	lint.Usage = func() {
 		_, _ = fmt.Fprintln(os.Stderr, "Usage: dmt lint [OPTIONS] [dirs...]")
@@ -49,6 +56,19 @@ func InitLintFlagSet() *pflag.FlagSet {
 	return lint
 }
 
+func InitCacheFlagSet() *pflag.FlagSet {
+	cacheFlags := pflag.NewFlagSet("cache", pflag.ContinueOnError)
+
+	cacheFlags.StringVar(&CacheDir, "cache-dir", cache.DefaultDir(), "cache directory")
+
+	cacheFlags.Usage = func() {
+		_, _ = fmt.Fprintln(os.Stderr, "Usage: dmt cache [clean|status] [OPTIONS]")
+		cacheFlags.PrintDefaults()
+	}
+
+	return cacheFlags
+}
+
 func InitGenFlagSet() *pflag.FlagSet {
 	gen := pflag.NewFlagSet("gen", pflag.ContinueOnError)
 
@@ -0,0 +1,58 @@
+package helm
+
+import (
+	"fmt"
+	"slices"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/apiversion"
+)
+
+const (
+	vpaAPIVersion = "autoscaling.k8s.io/v1/VerticalPodAutoscaler"
+)
+
+// DefaultCapabilities returns a copy of helm default capabilities extended with the VPA API version.
+func DefaultCapabilities() *chartutil.Capabilities {
+	caps := chartutil.DefaultCapabilities.Copy()
+	caps.APIVersions = appendMissing(slices.Clone(caps.APIVersions), vpaAPIVersion)
+
+	return caps
+}
+
+// NewCapabilities returns capabilities of a cluster running the kubeVersion Kubernetes version.
+// The API versions set contains built-in group/versions served by this version, the VPA API version
+// and extra apiVersions.
+func NewCapabilities(kubeVersion string, apiVersions []string) (*chartutil.Capabilities, error) {
+	kv, err := chartutil.ParseKubeVersion(kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubernetes version %q: %w", kubeVersion, err)
+	}
+
+	target, err := version.ParseGeneric(kv.Version)
+	if err != nil {
+		return nil, fmt.Errorf("parse kubernetes version %q: %w", kubeVersion, err)
+	}
+
+	vers := chartutil.VersionSet(apiversion.ServedGroupVersions(target))
+	vers = appendMissing(vers, vpaAPIVersion)
+	vers = appendMissing(vers, apiVersions...)
+
+	caps := chartutil.DefaultCapabilities.Copy()
+	caps.KubeVersion = *kv
+	caps.APIVersions = vers
+
+	return caps, nil
+}
+
+func appendMissing(vers chartutil.VersionSet, apiVersions ...string) chartutil.VersionSet {
+	for _, apiVersion := range apiVersions {
+		if !vers.Has(apiVersion) {
+			vers = append(vers, apiVersion)
+		}
+	}
+
+	return vers
+}
@@ -0,0 +1,46 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCapabilities(t *testing.T) {
+	caps, err := NewCapabilities("1.24", []string{"monitoring.coreos.com/v1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.24.0", caps.KubeVersion.Version)
+	assert.Equal(t, "24", caps.KubeVersion.Minor)
+	assert.True(t, caps.APIVersions.Has("policy/v1beta1"))
+	assert.True(t, caps.APIVersions.Has("autoscaling/v2"))
+	assert.False(t, caps.APIVersions.Has("extensions/v1beta1"))
+	assert.False(t, caps.APIVersions.Has("flowcontrol.apiserver.k8s.io/v1"))
+	assert.True(t, caps.APIVersions.Has(vpaAPIVersion))
+	assert.True(t, caps.APIVersions.Has("monitoring.coreos.com/v1"))
+
+	caps, err = NewCapabilities("1.29.2", nil)
+	require.NoError(t, err)
+
+	assert.False(t, caps.APIVersions.Has("policy/v1beta1"))
+	assert.True(t, caps.APIVersions.Has("flowcontrol.apiserver.k8s.io/v1"))
+	assert.False(t, caps.APIVersions.Has("flowcontrol.apiserver.k8s.io/v1beta2"))
+	assert.False(t, caps.APIVersions.Has("storage.k8s.io/v1beta1"))
+
+	caps, err = NewCapabilities("1.26", nil)
+	require.NoError(t, err)
+
+	assert.True(t, caps.APIVersions.Has("storage.k8s.io/v1beta1"))
+
+	_, err = NewCapabilities("latest", nil)
+	require.Error(t, err)
+}
+
+func TestDefaultCapabilitiesIsCopy(t *testing.T) {
+	first := DefaultCapabilities()
+	second := DefaultCapabilities()
+
+	assert.Equal(t, first.APIVersions, second.APIVersions)
+	assert.True(t, first.APIVersions.Has(vpaAPIVersion))
+}
@@ -0,0 +1,30 @@
+package helm
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// templateLocationRe matches template locations in text/template and helm errors:
+//
+//	template: module/templates/a.yaml:12:5: executing ...
+//	parse error at (module/templates/a.yaml:7): ...
+//	execution error at (module/templates/a.yaml:3:4): ...
+var templateLocationRe = regexp.MustCompile(`(?:template: |\()([^\s:()]+):(\d+)(?::\d+)?`)
+
+// TemplateErrorLocation returns the template file and line the render error points to.
+// The file is empty if the error does not contain a template location.
+func TemplateErrorLocation(err error) (file string, line int) {
+	if err == nil {
+		return "", 0
+	}
+
+	match := templateLocationRe.FindStringSubmatch(err.Error())
+	if match == nil {
+		return "", 0
+	}
+
+	line, _ = strconv.Atoi(match[2])
+
+	return match[1], line
+}
@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateErrorLocation(t *testing.T) {
+	tests := []struct {
+		err  string
+		file string
+		line int
+	}{
+		{
+			err:  `helm chart render: template: test/templates/a.yaml:12:5: executing "test/templates/a.yaml" at <.Values.x.y>: nil pointer evaluating interface {}.y`,
+			file: "test/templates/a.yaml",
+			line: 12,
+		},
+		{
+			err:  `helm chart render: parse error at (test/templates/_helpers.tpl:7): function "foo" not defined`,
+			file: "test/templates/_helpers.tpl",
+			line: 7,
+		},
+		{
+			err:  `helm chart render: execution error at (test/templates/b.yaml:3:4): value is required`,
+			file: "test/templates/b.yaml",
+			line: 3,
+		},
+		{
+			err: `open .namespace: no such file or directory`,
+		},
+	}
+
+	for _, tt := range tests {
+		file, line := TemplateErrorLocation(errors.New(tt.err))
+		assert.Equal(t, tt.file, file, tt.err)
+		assert.Equal(t, tt.line, line, tt.err)
+	}
+}
@@ -0,0 +1,149 @@
+package helm
+
+import (
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// UndefinedInclude is an include or template call of a helper not defined by the chart and its dependencies
+type UndefinedInclude struct {
+	Template string
+	// Path is the template path, e.g. module/templates/a.yaml
+	Path string
+	Line int
+}
+
+// UndefinedIncludes returns calls of helpers not defined in any template of the chart and its dependencies.
+// Helm shares defined templates between the chart and all its dependencies, so they are checked together.
+// Calls with names computed at render time and templates which can't be parsed are skipped.
+func UndefinedIncludes(ch *chart.Chart) []UndefinedInclude {
+	defined := make(map[string]struct{})
+	var trees []*parse.Tree
+
+	for templatePath, data := range chartTemplates(ch, "") {
+		treeSet := make(map[string]*parse.Tree)
+		tree := parse.New(templatePath)
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(string(data), "{{", "}}", treeSet); err != nil {
+			continue
+		}
+
+		for name, t := range treeSet {
+			defined[name] = struct{}{}
+			trees = append(trees, t)
+		}
+	}
+
+	var result []UndefinedInclude
+	for _, tree := range trees {
+		walkTemplateCalls(tree.Root, func(node parse.Node, name string) {
+			if _, ok := defined[name]; ok {
+				return
+			}
+
+			location, _ := tree.ErrorContext(node)
+			result = append(result, newUndefinedInclude(name, location))
+		})
+	}
+
+	slices.SortFunc(result, func(a, b UndefinedInclude) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return strings.Compare(a.Template, b.Template)
+	})
+
+	return slices.Compact(result)
+}
+
+// chartTemplates returns templates of the chart and its dependencies by paths helm renders them with.
+func chartTemplates(ch *chart.Chart, parent string) map[string][]byte {
+	chartPath := ch.Name()
+	if parent != "" {
+		chartPath = path.Join(parent, "charts", ch.Name())
+	}
+
+	result := make(map[string][]byte)
+	for _, t := range ch.Templates {
+		result[path.Join(chartPath, t.Name)] = t.Data
+	}
+	for _, dep := range ch.Dependencies() {
+		for name, data := range chartTemplates(dep, chartPath) {
+			result[name] = data
+		}
+	}
+
+	return result
+}
+
+// walkTemplateCalls calls fn for `template` actions and `include` function calls with constant names.
+func walkTemplateCalls(node parse.Node, fn func(node parse.Node, name string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplateCalls(child, fn)
+		}
+	case *parse.ActionNode:
+		walkTemplateCalls(n.Pipe, fn)
+	case *parse.TemplateNode:
+		fn(n, n.Name)
+		walkTemplateCalls(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplateCalls(cmd, fn)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) > 1 {
+			if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "include" {
+				if name, ok := n.Args[1].(*parse.StringNode); ok {
+					fn(n, name.Text)
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			walkTemplateCalls(arg, fn)
+		}
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	}
+}
+
+func walkBranch(n *parse.BranchNode, fn func(node parse.Node, name string)) {
+	walkTemplateCalls(n.Pipe, fn)
+	walkTemplateCalls(n.List, fn)
+	walkTemplateCalls(n.ElseList, fn)
+}
+
+// newUndefinedInclude parses the node location in the `path:line:column` form.
+func newUndefinedInclude(name, location string) UndefinedInclude {
+	result := UndefinedInclude{Template: name, Path: location}
+
+	parts := strings.Split(location, ":")
+	if len(parts) >= 3 {
+		line, err := strconv.Atoi(parts[len(parts)-2])
+		if err == nil {
+			result.Path = strings.Join(parts[:len(parts)-2], ":")
+			result.Line = line
+		}
+	}
+
+	return result
+}
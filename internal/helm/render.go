@@ -10,9 +10,10 @@ import (
 )
 
 type Renderer struct {
-	Name      string
-	Namespace string
-	LintMode  bool
+	Name         string
+	Namespace    string
+	LintMode     bool
+	Capabilities *chartutil.Capabilities
 }
 
 func (r Renderer) RenderChartFromDir(dir, values string) (files map[string]string, err error) {
@@ -44,20 +45,12 @@ func (r Renderer) RenderChart(c *chart.Chart, values string) (files map[string]s
 		IsUpgrade: true,
 	}
 
-	caps := chartutil.DefaultCapabilities
-	vers := []string(caps.APIVersions)
-
-	var found bool
-	for _, ver := range vers {
-		found = ver == "autoscaling.k8s.io/v1/VerticalPodAutoscaler"
-	}
-	if !found {
-		vers = append(vers, "autoscaling.k8s.io/v1/VerticalPodAutoscaler")
+	caps := r.Capabilities
+	if caps == nil {
+		caps = DefaultCapabilities()
 	}
 
-	caps.APIVersions = vers
-
-	valuesToRender, err := chartutil.ToRenderValues(c, vals, releaseOptions, nil)
+	valuesToRender, err := chartutil.ToRenderValues(c, vals, releaseOptions, caps)
 	if err != nil {
 		return nil, fmt.Errorf("helm chart prepare render values: %w", err)
 	}
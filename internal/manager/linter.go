@@ -2,6 +2,7 @@ package manager
 
 import (
 	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
 	"github.com/deckhouse/dmt/pkg/errors"
 )
 
@@ -12,3 +13,11 @@ type Linter interface {
 }
 
 type LinterList []Linter
+
+// RepositoryLinter checks objects rendered by all modules for the same target Kubernetes version.
+// Repository linters run after module linters.
+type RepositoryLinter interface {
+	RunRepository(store *storage.RepositoryStore) (errors.LintRuleErrorsList, error)
+	Name() string
+	Desc() string
+}
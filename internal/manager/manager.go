@@ -1,9 +1,11 @@
 package manager
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	k8s_resources "github.com/deckhouse/dmt/pkg/linters/k8s-resources"
@@ -11,19 +13,30 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/sourcegraph/conc/pool"
+	"helm.sh/helm/v3/pkg/chartutil"
 
+	"github.com/deckhouse/dmt/internal/cache"
 	"github.com/deckhouse/dmt/internal/flags"
+	internalhelm "github.com/deckhouse/dmt/internal/helm"
 	"github.com/deckhouse/dmt/internal/logger"
 	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
 	"github.com/deckhouse/dmt/pkg/config"
 	"github.com/deckhouse/dmt/pkg/errors"
 	"github.com/deckhouse/dmt/pkg/linters/container"
+	"github.com/deckhouse/dmt/pkg/linters/dashboards"
 	"github.com/deckhouse/dmt/pkg/linters/helm"
+	highavailability "github.com/deckhouse/dmt/pkg/linters/high-availability"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/crd"
 	"github.com/deckhouse/dmt/pkg/linters/license"
+	moduleyaml "github.com/deckhouse/dmt/pkg/linters/module-yaml"
 	no_cyrillic "github.com/deckhouse/dmt/pkg/linters/no-cyrillic"
 	"github.com/deckhouse/dmt/pkg/linters/openapi"
 	"github.com/deckhouse/dmt/pkg/linters/probes"
 	"github.com/deckhouse/dmt/pkg/linters/rbac"
+	"github.com/deckhouse/dmt/pkg/linters/references"
+	"github.com/deckhouse/dmt/pkg/linters/repository"
+	"github.com/deckhouse/dmt/pkg/linters/values"
 )
 
 const (
@@ -31,20 +44,43 @@ const (
 	ModuleYamlFilename  = "module.yaml"
 	HooksDir            = "hooks"
 	ImagesDir           = "images"
+
+	// LoadErrorID is used for modules failed to be discovered, loaded or rendered
+	LoadErrorID = "module-load"
+	// UndefinedIncludeID is used for include and template calls of helpers not defined anywhere
+	UndefinedIncludeID = "undefined-include"
+	// ManifestErrorID is used for rendered documents which can't be parsed, other documents are still linted
+	ManifestErrorID = "manifest"
 )
 
 type Manager struct {
 	cfg     *config.Config
 	Linters LinterList
-	Modules []*module.Module
+	// RepositoryLinters run on objects of all modules
+	RepositoryLinters []RepositoryLinter
+	Modules           []*module.Module
 
 	lintersMap map[string]Linter
+	// paths contains discovered modules, every module is loaded once per capabilities entry
+	paths        []string
+	capabilities []*chartutil.Capabilities
+	// cache stores linter results between runs, it is disabled if nil
+	cache *cache.Cache
+	// loadErrors contains modules discovery, load and render failures
+	loadErrors errors.LintRuleErrorsList
+	// crdRegistry contains schemas of CustomResourceDefinitions of modules and third-party CustomResourceDefinitions
+	crdRegistry *crd.Registry
 }
 
 func NewManager(dirs []string, cfg *config.Config) *Manager {
 	m := &Manager{
-		cfg: cfg,
+		cfg:   cfg,
+		cache: cache.New(flags.CacheDir, flags.Version),
 	}
+	module.SetCache(m.cache)
+	setDuplicateRules(cfg.LintersSettings.K8SResources.DuplicateObjects)
+	setWarningIDs(k8s_resources.DuplicateWarningID, monitoring.UnknownAnnotationID)
+	logger.CheckErr(module.SetLibraryCharts(slices.Concat(flags.LibraryChartDirs, cfg.LibraryChartDirs)))
 
 	// fill all linters
 	m.Linters = []Linter{
@@ -57,6 +93,15 @@ func NewManager(dirs []string, cfg *config.Config) *Manager {
 		helm.New(&cfg.LintersSettings.Helm),
 		rbac.New(&cfg.LintersSettings.Rbac),
 		monitoring.New(&cfg.LintersSettings.Monitoring),
+		moduleyaml.New(&cfg.LintersSettings.ModuleYaml),
+		values.New(&cfg.LintersSettings.Values),
+		dashboards.New(&cfg.LintersSettings.Dashboards),
+		highavailability.New(&cfg.LintersSettings.HighAvailability),
+		references.New(&cfg.LintersSettings.References),
+	}
+
+	m.RepositoryLinters = []RepositoryLinter{
+		repository.New(&cfg.LintersSettings.Repository),
 	}
 
 	m.lintersMap = make(map[string]Linter)
@@ -69,61 +114,112 @@ func NewManager(dirs []string, cfg *config.Config) *Manager {
 		m.Linters = append(m.Linters, linter)
 	}
 
-	var paths []string
-
 	for i := range dirs {
 		dir, err := homedir.Expand(dirs[i])
 		if err != nil {
-			logger.ErrorF("Failed to expand home dir: %v", err)
+			m.loadErrors.Merge(newLoadError(dirs[i], dirs[i], "", fmt.Errorf("expand home dir: %w", err)))
 			continue
 		}
 		result, err := getModulePaths(dir)
 		if err != nil {
-			logger.ErrorF("Error getting module paths: %v", err)
+			m.loadErrors.Merge(newLoadError(filepath.Base(dir), dir, "", fmt.Errorf("discover modules: %w", err)))
 			continue
 		}
-		paths = append(paths, result...)
+		m.paths = append(m.paths, result...)
 	}
 
-	for i := range paths {
-		moduleName := filepath.Base(paths[i])
-		logger.DebugF("Found `%s` module", moduleName)
-		mdl, err := module.NewModule(paths[i])
-		if err != nil {
-			logger.ErrorF("Cannot create module `%s`: %s", moduleName, err)
-			continue
+	var err error
+	m.capabilities, err = kubeCapabilities(cfg.KubernetesVersions)
+	logger.CheckErr(err)
+
+	m.crdRegistry, err = crd.NewRegistry(slices.Concat(m.crdDirs(), cfg.CRDDirs)...)
+	logger.CheckErr(err)
+	crd.SetRegistry(m.crdRegistry)
+
+	logger.InfoF("Found %d modules", len(m.paths))
+
+	return m
+}
+
+// setDuplicateRules sets duplicate objects policies before modules are loaded, duplicates are found by the object store.
+func setDuplicateRules(policies []config.DuplicateObjectPolicy) {
+	rules := make([]storage.DuplicateRule, 0, len(policies))
+	for _, p := range policies {
+		rules = append(rules, storage.DuplicateRule{
+			Kind:      p.Kind,
+			Namespace: p.Namespace,
+			Name:      p.Name,
+			Policy:    storage.DuplicatePolicy(p.Policy),
+		})
+	}
+	storage.SetDuplicateRules(rules)
+}
+
+// setWarningIDs adds IDs of findings which are always warnings, e.g. duplicates with the warn policy, to the configured ones.
+func setWarningIDs(ids ...string) {
+	for _, id := range ids {
+		if !slices.Contains(errors.WarningsOnly, id) {
+			errors.WarningsOnly = append(errors.WarningsOnly, id)
 		}
-		m.Modules = append(m.Modules, mdl)
 	}
+}
 
-	logger.InfoF("Found %d modules", len(m.Modules))
+// crdDirs returns crds directories of discovered modules
+func (m *Manager) crdDirs() []string {
+	var result []string
+	for _, path := range m.paths {
+		dir := filepath.Join(path, "crds")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			result = append(result, dir)
+		}
+	}
 
-	return m
+	return result
 }
 
+// Run loads modules and runs linters on them.
+// Every linter of a loaded module is submitted to the linters pool separately right after the module is loaded,
+// so linting starts before all modules are loaded and a slow linter does not hold other linters of the module.
 func (m *Manager) Run() errors.LintRuleErrorsList {
 	result := errors.LintRuleErrorsList{}
+	result.Merge(m.loadErrors)
+
+	// every module render has its own slot to keep the modules order independent of the loading order
+	modules := make([]*module.Module, len(m.paths)*len(m.capabilities))
 
 	var ch = make(chan errors.LintRuleErrorsList)
 	go func() {
-		var g = pool.New().WithMaxGoroutines(flags.LintersLimit)
-		for i := range m.Modules {
-			logger.InfoF("Run linters for `%s` module", m.Modules[i].GetName())
-			for j := range m.Linters {
-				g.Go(func() {
-					logger.DebugF("Running linter `%s` on module `%s`", m.Linters[j].Name(), m.Modules[i].GetName())
-					errs, err := m.Linters[j].Run(m.Modules[i])
-					if err != nil {
-						logger.ErrorF("Error running linter `%s`: %s\n", m.Linters[j].Name(), err)
+		// loaders only submit linters, linters never submit tasks, so the pools can't block each other
+		var loaders = pool.New().WithMaxGoroutines(flags.LintersLimit)
+		var linters = pool.New().WithMaxGoroutines(flags.LintersLimit)
+		for i := range m.paths {
+			for j, caps := range m.capabilities {
+				loaders.Go(func() {
+					mdl, errs := m.loadModule(m.paths[i], caps)
+					if mdl == nil {
+						ch <- errs
 						return
 					}
+					modules[i*len(m.capabilities)+j] = mdl
 					if errs.ConvertToError() != nil {
 						ch <- errs
 					}
+
+					logger.InfoF("Run linters for `%s` module", mdl.GetName())
+					for _, linter := range m.Linters {
+						linters.Go(func() {
+							m.runModuleLinter(linter, mdl, ch)
+						})
+					}
 				})
 			}
 		}
-		g.Wait()
+		loaders.Wait()
+		linters.Wait()
+
+		m.Modules = slices.DeleteFunc(modules, func(mdl *module.Module) bool { return mdl == nil })
+		m.runRepositoryLinters(ch)
+
 		close(ch)
 	}()
 
@@ -134,6 +230,190 @@ func (m *Manager) Run() errors.LintRuleErrorsList {
 	return result
 }
 
+func (m *Manager) loadModule(path string, caps *chartutil.Capabilities) (*module.Module, errors.LintRuleErrorsList) {
+	moduleName := filepath.Base(path)
+	logger.DebugF("Found `%s` module", moduleName)
+
+	mdl, err := module.NewModule(path, caps)
+	var includesErr *module.UndefinedIncludesError
+	if err != nil {
+		// the render may fail for another reason, so undefined helpers are reported along with the render error
+		errs := newLoadError(moduleName, path, kubeVersion(caps), err)
+		if stderrors.As(err, &includesErr) {
+			errs.Merge(newUndefinedIncludeErrors(moduleName, kubeVersion(caps), includesErr.Includes))
+		}
+		return nil, errs
+	}
+
+	errs := newManifestErrors(mdl)
+	errs.Merge(newUndefinedIncludeErrors(mdl.GetName(), mdl.GetKubeVersion(), mdl.GetUndefinedIncludes()))
+
+	return mdl, errs
+}
+
+func (m *Manager) runModuleLinter(linter Linter, mdl *module.Module, ch chan<- errors.LintRuleErrorsList) {
+	logger.DebugF("Running linter `%s` on module `%s`", linter.Name(), mdl.GetName())
+	errs, err := m.runLinter(linter, mdl)
+	if err != nil {
+		logger.ErrorF("Error running linter `%s`: %s\n", linter.Name(), err)
+		return
+	}
+	if errs.ConvertToError() != nil {
+		errs.SetKubeVersion(mdl.GetKubeVersion())
+		ch <- errs
+	}
+}
+
+// runRepositoryLinters runs repository linters on modules grouped by the target Kubernetes version,
+// so renders of the same module for different versions do not collide.
+func (m *Manager) runRepositoryLinters(ch chan<- errors.LintRuleErrorsList) {
+	stores := make(map[string]*storage.RepositoryStore)
+	var kubeVersions []string
+	for _, mdl := range m.Modules {
+		kubeVersion := mdl.GetKubeVersion()
+		if stores[kubeVersion] == nil {
+			stores[kubeVersion] = storage.NewRepositoryStore(kubeVersion)
+			kubeVersions = append(kubeVersions, kubeVersion)
+		}
+		stores[kubeVersion].Add(mdl.GetName(), mdl.GetPath(), mdl.GetNamespace(), mdl.GetObjectStore())
+	}
+
+	for _, kubeVersion := range kubeVersions {
+		for _, linter := range m.RepositoryLinters {
+			logger.DebugF("Running repository linter `%s`", linter.Name())
+			errs, err := linter.RunRepository(stores[kubeVersion])
+			if err != nil {
+				logger.ErrorF("Error running repository linter `%s`: %s\n", linter.Name(), err)
+				continue
+			}
+			if errs.ConvertToError() != nil {
+				errs.SetKubeVersion(kubeVersion)
+				ch <- errs
+			}
+		}
+	}
+}
+
+// runLinter runs the linter or takes its results from the cache.
+// Results are cached by the module content, the linter name, the linters settings and loaded CustomResourceDefinitions.
+func (m *Manager) runLinter(linter Linter, mdl *module.Module) (errors.LintRuleErrorsList, error) {
+	if mdl.GetHash() == "" {
+		return linter.Run(mdl)
+	}
+
+	key, err := m.cache.Key(mdl.GetHash(), linter.Name(), m.cfg.LintersSettings, m.crdRegistry.Hash())
+	if err != nil {
+		return linter.Run(mdl)
+	}
+
+	var errs errors.LintRuleErrorsList
+	if m.cache.Get(cache.ResultsKind, key, &errs) {
+		logger.DebugF("Linter `%s` results for module `%s` are taken from the cache", linter.Name(), mdl.GetName())
+		return errs, nil
+	}
+
+	errs, err = linter.Run(mdl)
+	if err != nil {
+		return errs, err
+	}
+
+	if err := m.cache.Put(cache.ResultsKind, key, errs); err != nil {
+		logger.WarnF("Cannot cache linter `%s` results: %s", linter.Name(), err)
+	}
+
+	return errs, nil
+}
+
+// newLoadError reports the module which can't be linted.
+// The object points to the template file and line if the error is a helm render error.
+func newLoadError(moduleName, path, kubeVersion string, err error) errors.LintRuleErrorsList {
+	logger.DebugF("Cannot create module `%s`: %s", moduleName, err)
+
+	objectID := path
+	if file, line := internalhelm.TemplateErrorLocation(err); file != "" {
+		objectID = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	errs := errors.LintRuleErrorsList{}
+	errs.Add(errors.NewLintRuleError(
+		LoadErrorID,
+		objectID,
+		moduleName,
+		nil,
+		"cannot load module: %s",
+		err,
+	))
+	errs.SetKubeVersion(kubeVersion)
+
+	return errs
+}
+
+// newUndefinedIncludeErrors reports calls of undefined helpers, they fail the module render if executed.
+func newUndefinedIncludeErrors(moduleName, kubeVersion string, includes []internalhelm.UndefinedInclude) errors.LintRuleErrorsList {
+	errs := errors.LintRuleErrorsList{}
+	for _, include := range includes {
+		errs.Add(errors.NewLintRuleError(
+			UndefinedIncludeID,
+			fmt.Sprintf("%s:%d", include.Path, include.Line),
+			moduleName,
+			include.Template,
+			"template %q is not defined by the chart and library charts",
+			include.Template,
+		))
+	}
+	errs.SetKubeVersion(kubeVersion)
+
+	return errs
+}
+
+// newManifestErrors reports rendered documents of the module which can't be parsed.
+// Lines are lines of the rendered output of the template, so they are reported in the message, not in the object.
+func newManifestErrors(mdl *module.Module) errors.LintRuleErrorsList {
+	errs := errors.LintRuleErrorsList{}
+	for _, manifestErr := range mdl.GetManifestErrors() {
+		errs.Add(errors.NewLintRuleError(
+			ManifestErrorID,
+			manifestErr.Path,
+			mdl.GetName(),
+			nil,
+			"cannot parse rendered document %d at line %d of the rendered output: %s",
+			manifestErr.Document,
+			manifestErr.Line,
+			manifestErr.Err,
+		))
+	}
+	errs.SetKubeVersion(mdl.GetKubeVersion())
+
+	return errs
+}
+
+func kubeVersion(caps *chartutil.Capabilities) string {
+	if caps == nil {
+		return ""
+	}
+
+	return caps.KubeVersion.Version
+}
+
+// kubeCapabilities returns capabilities for every target Kubernetes version.
+// Modules are rendered once with helm default capabilities if no versions are configured.
+func kubeCapabilities(versions []config.KubernetesVersion) ([]*chartutil.Capabilities, error) {
+	if len(versions) == 0 {
+		return []*chartutil.Capabilities{nil}, nil
+	}
+
+	result := make([]*chartutil.Capabilities, 0, len(versions))
+	for _, v := range versions {
+		caps, err := internalhelm.NewCapabilities(v.Version, v.APIVersions)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, caps)
+	}
+
+	return result, nil
+}
+
 func isExistsOnFilesystem(parts ...string) bool {
 	_, err := os.Stat(filepath.Join(parts...))
 	return err == nil
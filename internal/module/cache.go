@@ -0,0 +1,114 @@
+package module
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/deckhouse/dmt/internal/cache"
+	"github.com/deckhouse/dmt/internal/fsutils"
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+// renderCache stores rendered chart files between runs. The cache is disabled if it is nil.
+var renderCache *cache.Cache
+
+// SetCache enables the cache of rendered chart files and module hashes.
+func SetCache(c *cache.Cache) {
+	renderCache = c
+}
+
+// chartDigest contains everything the chart render depends on
+type chartDigest struct {
+	Metadata     *chart.Metadata
+	Templates    []*chart.File
+	Files        []*chart.File
+	Values       map[string]any
+	Schema       []byte
+	Dependencies []chartDigest
+}
+
+func newChartDigest(ch *chart.Chart) chartDigest {
+	digest := chartDigest{
+		Metadata:  ch.Metadata,
+		Templates: ch.Templates,
+		Files:     ch.Files,
+		Values:    ch.Values,
+		Schema:    ch.Schema,
+	}
+	for _, dep := range ch.Dependencies() {
+		digest.Dependencies = append(digest.Dependencies, newChartDigest(dep))
+	}
+
+	return digest
+}
+
+// fileDigest is a module file linters may read
+type fileDigest struct {
+	Path string
+	Hash string
+}
+
+// moduleFilesDigest returns hashes of all module files.
+// Symlinks to directories are represented by their targets.
+func moduleFilesDigest(path string) ([]fileDigest, error) {
+	files, err := fsutils.GetFiles(path, false)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	result := make([]fileDigest, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			target, linkErr := os.Readlink(file)
+			if linkErr != nil {
+				return nil, errors.Join(err, linkErr)
+			}
+			content = []byte(target)
+		}
+
+		result = append(result, fileDigest{Path: rel, Hash: storage.NewSHA256(content)})
+	}
+
+	return result, nil
+}
+
+// objectsDigest returns hashes of all rendered objects in a stable order.
+func objectsDigest(objectStore *storage.UnstructuredObjectStore) []fileDigest {
+	result := make([]fileDigest, 0, len(objectStore.Storage))
+	for index, object := range objectStore.Storage {
+		result = append(result, fileDigest{Path: object.Path + ":" + index.AsString(), Hash: object.Hash})
+	}
+	slices.SortFunc(result, func(a, b fileDigest) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result
+}
+
+// computeHash returns the hash of everything linters see: the module files, the rendered objects and
+// the chart with injected library charts and values, which on demand renders (e.g. high availability ones) depend on.
+// It returns an empty string if the cache is disabled.
+func (m *Module) computeHash() (string, error) {
+	if renderCache == nil {
+		return "", nil
+	}
+
+	files, err := moduleFilesDigest(m.path)
+	if err != nil {
+		return "", err
+	}
+
+	return renderCache.Key(m.path, m.capabilities, files, objectsDigest(m.objectStore), newChartDigest(m.chart), m.values)
+}
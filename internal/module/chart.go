@@ -0,0 +1,115 @@
+package module
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/ignore"
+	"sigs.k8s.io/yaml"
+)
+
+var utf8bom = []byte{0xEF, 0xBB, 0xBF}
+
+// loadChart loads the module helm chart.
+// Modules with module.yaml may omit Chart.yaml, Deckhouse generates chart metadata for them
+// and so does loadChart.
+func loadChart(path, name string) (*chart.Chart, error) {
+	_, err := os.Stat(filepath.Join(path, ChartConfigFilename))
+	if err == nil {
+		return loader.Load(path)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	files, err := loadChartFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := yaml.Marshal(chart.Metadata{
+		APIVersion: chart.APIVersionV2,
+		Name:       name,
+		Version:    "0.0.1",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files = append(files, &loader.BufferedFile{Name: ChartConfigFilename, Data: metadata})
+
+	return loader.LoadFiles(files)
+}
+
+// loadChartFiles reads chart files the same way helm loader.LoadDir does, following .helmignore rules.
+func loadChartFiles(path string) ([]*loader.BufferedFile, error) {
+	topdir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := ignore.Empty()
+	ifile := filepath.Join(topdir, ignore.HelmIgnore)
+	if _, err := os.Stat(ifile); err == nil {
+		rules, err = ignore.ParseFile(ifile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rules.AddDefaults()
+
+	var files []*loader.BufferedFile
+
+	var walk func(dir, prefix string) error
+	walk = func(dir, prefix string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			fullPath := filepath.Join(dir, entry.Name())
+			// follow symlinks as helm does
+			fi, err := os.Stat(fullPath)
+			if err != nil {
+				return err
+			}
+
+			n := filepath.ToSlash(filepath.Join(prefix, entry.Name()))
+			if rules.Ignore(n, fi) {
+				continue
+			}
+
+			if fi.IsDir() {
+				if err := walk(fullPath, n); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if !fi.Mode().IsRegular() {
+				return fmt.Errorf("cannot load irregular file %s as it has file mode type bits set", fullPath)
+			}
+
+			data, err := os.ReadFile(fullPath)
+			if err != nil {
+				return fmt.Errorf("error reading %s: %w", n, err)
+			}
+
+			files = append(files, &loader.BufferedFile{Name: n, Data: bytes.TrimPrefix(data, utf8bom)})
+		}
+
+		return nil
+	}
+
+	if err := walk(topdir, ""); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -1,96 +1,107 @@
 package module
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
-	"strings"
+	"maps"
+	"slices"
 	"sync"
 
 	"github.com/mitchellh/hashstructure/v2"
-	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/chartutil"
 
+	"github.com/deckhouse/dmt/internal/cache"
 	"github.com/deckhouse/dmt/internal/helm"
+	"github.com/deckhouse/dmt/internal/logger"
 	"github.com/deckhouse/dmt/internal/storage"
 )
 
-var (
-	renderedTemplatesHash = sync.Map{}
-)
+// parsedObjectStores shares object stores between modules rendering identical files,
+// e.g. the same module rendered for several Kubernetes versions.
+// Object stores are read-only after parsing, so every module gets the store and is linted.
+var parsedObjectStores = sync.Map{}
+
+type parsedObjectStore struct {
+	once           sync.Once
+	store          *storage.UnstructuredObjectStore
+	manifestErrors []ManifestError
+}
 
-func RunRender(m *Module, values chartutil.Values, objectStore *storage.UnstructuredObjectStore) error {
+// RunRender renders the module chart with values and returns the object store of rendered objects
+// and rendered documents which can't be parsed.
+func RunRender(m *Module, values chartutil.Values) (*storage.UnstructuredObjectStore, []ManifestError, error) {
 	var renderer helm.Renderer
 	renderer.Name = m.GetName()
 	renderer.Namespace = m.GetNamespace()
 	renderer.LintMode = true
+	renderer.Capabilities = m.GetCapabilities()
 
-	files, err := renderer.RenderChartFromRawValues(m.GetChart(), values)
+	files, err := renderFiles(m, &renderer, values)
 	if err != nil {
-		return fmt.Errorf("helm chart render: %w", err)
+		return nil, nil, err
 	}
 
 	hash, err := hashstructure.Hash(files, hashstructure.FormatV2, nil)
 	if err != nil {
-		return fmt.Errorf("helm chart render: %w", err)
-	}
-
-	if _, ok := renderedTemplatesHash.Load(hash); ok {
-		return nil
+		return nil, nil, fmt.Errorf("helm chart render: %w", err)
 	}
 
-	defer renderedTemplatesHash.Store(hash, struct{}{})
+	// modules are rendered concurrently, the first one parses files and others wait for the result
+	value, _ := parsedObjectStores.LoadOrStore(hash, new(parsedObjectStore))
+	parsed := value.(*parsedObjectStore)
+	parsed.once.Do(func() {
+		parsed.store, parsed.manifestErrors = ParseObjects(files)
+	})
 
-	var docBytes []byte
+	return parsed.store, parsed.manifestErrors, nil
+}
 
-	for path, bigFile := range files {
-		scanner := bufio.NewScanner(strings.NewReader(bigFile))
-		scanner.Split(SplitAt("---"))
+// ParseObjects puts all documents of rendered files to a new object store.
+// Documents which can't be parsed are returned as manifest errors and skipped.
+func ParseObjects(files map[string]string) (*storage.UnstructuredObjectStore, []ManifestError) {
+	objectStore := storage.NewUnstructuredObjectStore()
 
-		for scanner.Scan() {
-			var node map[string]any
-			docBytes = scanner.Bytes()
+	var manifestErrors []ManifestError
 
-			err = yaml.Unmarshal(docBytes, &node)
+	// the first rendered object is kept on duplicates, so files are processed in a stable order
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		for _, doc := range splitDocuments(files[path]) {
+			node, line, err := doc.decode()
 			if err != nil {
-				return fmt.Errorf(manifestErrorMessage, err)
+				manifestErrors = append(manifestErrors, ManifestError{Path: path, Document: doc.index, Line: line, Err: err})
+				continue
 			}
 
 			if len(node) == 0 {
 				continue
 			}
 
-			err = objectStore.Put(path, node, docBytes)
-			if err != nil {
-				return fmt.Errorf("helm chart object already exists: %w", err)
-			}
+			objectStore.Put(path, node, doc.content)
 		}
 	}
 
-	return nil
+	return objectStore, manifestErrors
 }
 
-func SplitAt(substring string) func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		// Return nothing if at end of file and no data passed
-		if atEOF && len(data) == 0 {
-			return 0, nil, nil
-		}
+// renderFiles renders the chart or takes rendered files from the cache.
+func renderFiles(m *Module, renderer *helm.Renderer, values chartutil.Values) (map[string]string, error) {
+	key, err := renderCache.Key(newChartDigest(m.GetChart()), values, m.GetCapabilities(), renderer.Name, renderer.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
-		// Find the index of the input of the separator substring
-		if i := bytes.Index(data, []byte(substring)); i >= 0 {
-			return i + len(substring), data[0:i], nil
-		}
+	var files map[string]string
+	if renderCache.Get(cache.RendersKind, key, &files) {
+		return files, nil
+	}
 
-		// If at end of file with data return the data
-		if atEOF {
-			return len(data), data, nil
-		}
+	files, err = renderer.RenderChartFromRawValues(m.GetChart(), values)
+	if err != nil {
+		return nil, err
+	}
 
-		return
+	if err := renderCache.Put(cache.RendersKind, key, files); err != nil {
+		logger.WarnF("Cannot cache `%s` module render: %s", m.GetName(), err)
 	}
-}
 
-const (
-	manifestErrorMessage = `manifest unmarshal: %v`
-)
+	return files, nil
+}
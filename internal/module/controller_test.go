@@ -0,0 +1,44 @@
+package module
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/deckhouse/dmt/internal/helm"
+)
+
+func TestIdenticalRendersAreLinted(t *testing.T) {
+	files := map[string]string{
+		ModuleYamlFilename:           "name: identical\nnamespace: d8-identical\n",
+		"openapi/config-values.yaml": "type: object\nproperties: {}\n",
+		"openapi/values.yaml":        "type: object\nproperties: {}\n",
+		"templates/cm.yaml":          "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: identical\n  namespace: d8-identical\n",
+	}
+
+	root := t.TempDir()
+	ce, ee := filepath.Join(root, "ce"), filepath.Join(root, "ee")
+	writeFiles(t, ce, files)
+	writeFiles(t, ee, files)
+
+	caps129, err := helm.NewCapabilities("1.29", nil)
+	require.NoError(t, err)
+	caps130, err := helm.NewCapabilities("1.30", nil)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		path string
+		caps *chartutil.Capabilities
+	}{
+		{ce, caps129},
+		{ee, caps129},
+		{ce, caps130},
+	} {
+		m, err := NewModule(tt.path, tt.caps)
+		require.NoError(t, err)
+		assert.Len(t, m.GetStorage(), 1, "module %s", m)
+	}
+}
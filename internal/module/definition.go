@@ -0,0 +1,84 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ModuleYamlFilename = "module.yaml"
+	NamespaceFilename  = ".namespace"
+)
+
+// Definition contains module properties declared in the module.yaml file
+type Definition struct {
+	Name         string       `yaml:"name"`
+	Namespace    string       `yaml:"namespace"`
+	Weight       int          `yaml:"weight"`
+	Stage        string       `yaml:"stage"`
+	Tags         []string     `yaml:"tags"`
+	Requirements Requirements `yaml:"requirements"`
+	Descriptions Descriptions `yaml:"descriptions"`
+}
+
+// Requirements contains version constraints the module depends on
+type Requirements struct {
+	Kubernetes   string            `yaml:"kubernetes"`
+	Deckhouse    string            `yaml:"deckhouse"`
+	Bootstrapped bool              `yaml:"bootstrapped"`
+	Modules      map[string]string `yaml:"modules"`
+}
+
+type Descriptions struct {
+	En string `yaml:"en"`
+	Ru string `yaml:"ru"`
+}
+
+// ParseDefinition reads module.yaml from the module path.
+// It returns nil definition without error if the module does not have module.yaml.
+func ParseDefinition(path string) (*Definition, error) {
+	content, err := os.ReadFile(filepath.Join(path, ModuleYamlFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	def := new(Definition)
+	err = yaml.Unmarshal(content, def)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ModuleYamlFilename, err)
+	}
+
+	return def, nil
+}
+
+// resolveIdentity returns module name and namespace.
+// module.yaml is the primary source, Chart.yaml and .namespace are used as a fallback.
+func resolveIdentity(path string, def *Definition) (name, namespace string, err error) {
+	if def != nil {
+		name = def.Name
+		namespace = def.Namespace
+	}
+
+	if name == "" {
+		name, err = getModuleName(path)
+		if err != nil {
+			return "", "", fmt.Errorf("module name is not defined in %s: %w", ModuleYamlFilename, err)
+		}
+	}
+
+	if namespace == "" {
+		namespace, err = getNamespace(path)
+		if err != nil {
+			return "", "", fmt.Errorf("module namespace is not defined in %s: %w", ModuleYamlFilename, err)
+		}
+	}
+
+	return name, namespace, nil
+}
@@ -0,0 +1,84 @@
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestModuleYamlIdentity(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		ModuleYamlFilename: `
+name: test-module
+namespace: d8-test-module
+weight: 910
+stage: Preview
+tags: ["observability"]
+requirements:
+  kubernetes: ">= 1.27"
+  modules:
+    prometheus: ">= 0.0.0"
+descriptions:
+  en: Test module
+`,
+		"templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+		".helmignore":       "hooks\n",
+		"hooks/hook.go":     "package hooks\n",
+	})
+
+	def, err := ParseDefinition(dir)
+	require.NoError(t, err)
+	require.NotNil(t, def)
+	assert.Equal(t, 910, def.Weight)
+	assert.Equal(t, "Preview", def.Stage)
+	assert.Equal(t, ">= 1.27", def.Requirements.Kubernetes)
+	assert.Equal(t, map[string]string{"prometheus": ">= 0.0.0"}, def.Requirements.Modules)
+
+	name, namespace, err := resolveIdentity(dir, def)
+	require.NoError(t, err)
+	assert.Equal(t, "test-module", name)
+	assert.Equal(t, "d8-test-module", namespace)
+
+	ch, err := loadChart(dir, name)
+	require.NoError(t, err)
+	assert.Equal(t, "test-module", ch.Metadata.Name)
+	require.Len(t, ch.Templates, 1)
+	assert.Equal(t, "templates/cm.yaml", ch.Templates[0].Name)
+	for _, f := range ch.Files {
+		assert.NotEqual(t, "hooks/hook.go", f.Name, "helmignored files must not be loaded")
+	}
+}
+
+func TestChartYamlIdentityFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		ChartConfigFilename: "apiVersion: v2\nname: legacy\nversion: 0.0.1\n",
+		NamespaceFilename:   "d8-legacy\n",
+	})
+
+	def, err := ParseDefinition(dir)
+	require.NoError(t, err)
+	assert.Nil(t, def)
+
+	name, namespace, err := resolveIdentity(dir, def)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", name)
+	assert.Equal(t, "d8-legacy", namespace)
+
+	_, _, err = resolveIdentity(t.TempDir(), nil)
+	require.Error(t, err)
+}
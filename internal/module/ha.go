@@ -0,0 +1,69 @@
+package module
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/mohae/deepcopy"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+// haRender contains objects of the module rendered with the high availability mode turned on or off
+type haRender struct {
+	once  sync.Once
+	store *storage.UnstructuredObjectStore
+	err   error
+}
+
+// GetHAObjectStore renders the module with the `highAvailability` module and global values set to enabled
+// and returns rendered objects. Renders are done on the first call. It returns nil if the module has no values.
+func (m *Module) GetHAObjectStore(enabled bool) (*storage.UnstructuredObjectStore, error) {
+	if m == nil || m.values == nil {
+		return nil, nil
+	}
+
+	render := m.haRenders[enabled]
+	render.once.Do(func() {
+		render.store, _, render.err = RunRender(m, m.haValues(enabled))
+		if render.err != nil {
+			render.err = fmt.Errorf("render with highAvailability=%t: %w", enabled, render.err)
+		}
+	})
+
+	return render.store, render.err
+}
+
+// haValues returns values of the module with the high availability mode set the way helm_lib reads it:
+// the module `highAvailability` value takes precedence over the global one and the control plane discovery
+func (m *Module) haValues(enabled bool) chartutil.Values {
+	rawValues, _ := deepcopy.Copy(m.values["Values"]).(map[string]any)
+	if rawValues == nil {
+		rawValues = make(map[string]any)
+	}
+
+	setValue(rawValues, enabled, ToLowerCamel(m.GetName()), "highAvailability")
+	setValue(rawValues, enabled, "global", "highAvailability")
+	setValue(rawValues, enabled, "global", "discovery", "clusterControlPlaneIsHighlyAvailable")
+
+	values := maps.Clone(m.values)
+	values["Values"] = rawValues
+
+	return values
+}
+
+// setValue sets the nested value, missing or non-map parents are replaced with maps
+func setValue(values map[string]any, value any, path ...string) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := values[key].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			values[key] = child
+		}
+		values = child
+	}
+
+	values[path[len(path)-1]] = value
+}
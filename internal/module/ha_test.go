@@ -0,0 +1,32 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestHAValues(t *testing.T) {
+	m := &Module{
+		name: "cert-manager",
+		values: chartutil.Values{
+			"Values": map[string]any{
+				"certManager": map[string]any{"logLevel": "info"},
+				"global":      map[string]any{"discovery": "unknown"},
+			},
+		},
+	}
+
+	values := m.haValues(true)
+	assert.Equal(t, map[string]any{
+		"certManager": map[string]any{"logLevel": "info", "highAvailability": true},
+		"global": map[string]any{
+			"highAvailability": true,
+			"discovery":        map[string]any{"clusterControlPlaneIsHighlyAvailable": true},
+		},
+	}, values["Values"])
+
+	// values the module is rendered with are not changed
+	assert.Equal(t, map[string]any{"logLevel": "info"}, m.values["Values"].(map[string]any)["certManager"])
+}
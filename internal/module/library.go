@@ -0,0 +1,118 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+
+	"github.com/deckhouse/dmt/internal/helm"
+)
+
+// libraryChart contains files of a local library chart, it is loaded for every module separately
+// because helm links dependencies to their parent chart.
+type libraryChart struct {
+	name  string
+	files []*loader.BufferedFile
+}
+
+// libraryCharts are injected into module charts which do not vendor them
+var libraryCharts []libraryChart
+
+// SetLibraryCharts loads library charts (e.g. helm_lib) from dirs.
+// A dir is either a chart or a directory with charts.
+func SetLibraryCharts(dirs []string) error {
+	libraryCharts = nil
+
+	for _, dir := range dirs {
+		chartDirs, err := libraryChartDirs(dir)
+		if err != nil {
+			return err
+		}
+
+		for _, chartDir := range chartDirs {
+			files, err := loadChartFiles(chartDir)
+			if err != nil {
+				return fmt.Errorf("library chart %s: %w", chartDir, err)
+			}
+
+			ch, err := loader.LoadFiles(files)
+			if err != nil {
+				return fmt.Errorf("library chart %s: %w", chartDir, err)
+			}
+			if ch.Metadata.Type != "library" {
+				return fmt.Errorf("chart %s is not a library chart", chartDir)
+			}
+
+			if slices.ContainsFunc(libraryCharts, func(lib libraryChart) bool { return lib.name == ch.Name() }) {
+				return fmt.Errorf("library chart %s is found more than once", ch.Name())
+			}
+
+			libraryCharts = append(libraryCharts, libraryChart{name: ch.Name(), files: files})
+		}
+	}
+
+	return nil
+}
+
+func libraryChartDirs(dir string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ChartConfigFilename)); err == nil {
+		return []string{dir}, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("library chart dir: %w", err)
+	}
+
+	var result []string
+	for _, entry := range entries {
+		chartDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(chartDir, ChartConfigFilename)); err == nil {
+			result = append(result, chartDir)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("library chart dir %s does not contain charts", dir)
+	}
+
+	return result, nil
+}
+
+// addLibraryCharts adds library charts the module chart does not vendor to its dependencies.
+func addLibraryCharts(ch *chart.Chart) error {
+	for _, lib := range libraryCharts {
+		if slices.ContainsFunc(ch.Dependencies(), func(dep *chart.Chart) bool { return dep.Name() == lib.name }) {
+			continue
+		}
+
+		dep, err := loader.LoadFiles(lib.files)
+		if err != nil {
+			return fmt.Errorf("library chart %s: %w", lib.name, err)
+		}
+		ch.AddDependency(dep)
+	}
+
+	return nil
+}
+
+// UndefinedIncludesError is a render error of the module calling helpers not defined anywhere
+type UndefinedIncludesError struct {
+	Includes []helm.UndefinedInclude
+	Err      error
+}
+
+func (e *UndefinedIncludesError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UndefinedIncludesError) Unwrap() error {
+	return e.Err
+}
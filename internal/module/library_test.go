@@ -0,0 +1,96 @@
+package module
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deckhouse/dmt/internal/cache"
+	"github.com/deckhouse/dmt/internal/helm"
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+func TestLibraryCharts(t *testing.T) {
+	root := t.TempDir()
+	libDir, moduleDir := filepath.Join(root, "lib"), filepath.Join(root, "module")
+
+	writeFiles(t, filepath.Join(libDir, "helm_lib"), map[string]string{
+		ChartConfigFilename:     "apiVersion: v2\nname: helm_lib\nversion: 1.0.0\ntype: library\n",
+		"templates/_labels.tpl": `{{- define "helm_lib_module_labels" }}module: {{ .Chart.Name }}{{ end }}`,
+	})
+	writeFiles(t, moduleDir, map[string]string{
+		ModuleYamlFilename:           "name: library\nnamespace: d8-library\n",
+		"openapi/config-values.yaml": "type: object\nproperties: {}\n",
+		"openapi/values.yaml":        "type: object\nproperties: {}\n",
+		"templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: library
+  namespace: d8-library
+  labels:
+    {{- include "helm_lib_module_labels" . | nindent 4 }}
+{{- if .Values.disabled }}
+    {{- include "helm_lib_missing" . | nindent 4 }}
+{{- end }}
+`,
+	})
+
+	t.Cleanup(func() { libraryCharts = nil })
+
+	_, err := NewModule(moduleDir, nil)
+	var includesErr *UndefinedIncludesError
+	require.ErrorAs(t, err, &includesErr)
+	assert.Equal(t, []helm.UndefinedInclude{
+		{Template: "helm_lib_module_labels", Path: "library/templates/cm.yaml", Line: 7},
+		{Template: "helm_lib_missing", Path: "library/templates/cm.yaml", Line: 9},
+	}, includesErr.Includes)
+
+	require.NoError(t, SetLibraryCharts([]string{libDir}))
+
+	m, err := NewModule(moduleDir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []helm.UndefinedInclude{
+		{Template: "helm_lib_missing", Path: "library/templates/cm.yaml", Line: 9},
+	}, m.GetUndefinedIncludes())
+
+	cm := m.GetObjectStore().Get(storage.ResourceIndex{Kind: "ConfigMap", Name: "library", Namespace: "d8-library"})
+	assert.Equal(t, map[string]string{"module": "library"}, cm.Unstructured.GetLabels())
+}
+
+func TestModuleHashIncludesLibraryCharts(t *testing.T) {
+	root := t.TempDir()
+	libDir, moduleDir := filepath.Join(root, "lib"), filepath.Join(root, "module")
+
+	writeFiles(t, filepath.Join(libDir, "helm_lib"), map[string]string{
+		ChartConfigFilename:       "apiVersion: v2\nname: helm_lib\nversion: 1.0.0\ntype: library\n",
+		"templates/_replicas.tpl": `{{- define "helm_lib_replicas" }}1{{ end }}`,
+	})
+	writeFiles(t, moduleDir, map[string]string{
+		ModuleYamlFilename:    "name: library\nnamespace: d8-library\n",
+		"openapi/values.yaml": "type: object\nproperties: {}\n",
+		"templates/cm.yaml":   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: library\n  namespace: d8-library\n",
+	})
+
+	SetCache(cache.New(filepath.Join(root, "cache"), "test"))
+	t.Cleanup(func() {
+		SetCache(nil)
+		libraryCharts = nil
+	})
+
+	require.NoError(t, SetLibraryCharts([]string{libDir}))
+	m, err := NewModule(moduleDir, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.GetHash())
+
+	// library helpers unused by the default render may change on demand renders
+	writeFiles(t, filepath.Join(libDir, "helm_lib"), map[string]string{
+		"templates/_replicas.tpl": `{{- define "helm_lib_replicas" }}{{ if .Values.global.highAvailability }}2{{ else }}1{{ end }}{{ end }}`,
+	})
+	require.NoError(t, SetLibraryCharts([]string{libDir}))
+	changed, err := NewModule(moduleDir, nil)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, m.GetHash(), changed.GetHash())
+}
@@ -0,0 +1,128 @@
+package module
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestError is a rendered document which can't be parsed. Other documents of the module are still linted.
+type ManifestError struct {
+	// Path is the template path, e.g. module/templates/a.yaml
+	Path string
+	// Document is the zero-based index of the document in the rendered file
+	Document int
+	// Line is the line of the rendered file the error points to
+	Line int
+	Err  error
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("%s:%d: document %d: %v", e.Path, e.Line, e.Document, e.Err)
+}
+
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+// manifestDocument is a document of a rendered file
+type manifestDocument struct {
+	index int
+	// line is the line of the rendered file the document starts at
+	line    int
+	content []byte
+}
+
+// yamlLineRe matches line numbers in yaml errors, they are relative to the decoded document
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// splitDocuments splits the rendered file at `---` document markers.
+// Markers are recognized at the beginning of a line only, so `---` inside values and
+// indented block scalars does not split documents.
+// Leading comments and blank lines before the first marker are not a document.
+func splitDocuments(content string) []manifestDocument {
+	var (
+		result  []manifestDocument
+		current = manifestDocument{line: 1}
+		buf     bytes.Buffer
+	)
+
+	flush := func() {
+		if len(result) == 0 && current.line == 1 && isBlankDocument(buf.Bytes()) {
+			return
+		}
+		current.index = len(result)
+		current.content = bytes.Clone(buf.Bytes())
+		result = append(result, current)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if isDocumentMarker(text) && (line > 1 || buf.Len() > 0) {
+			flush()
+			buf.Reset()
+			current = manifestDocument{line: line}
+		}
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+	}
+	flush()
+
+	return result
+}
+
+func isDocumentMarker(line string) bool {
+	line = strings.TrimRight(line, "\r")
+	if !strings.HasPrefix(line, "---") {
+		return false
+	}
+
+	rest := line[len("---"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+func isBlankDocument(content []byte) bool {
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decode unmarshals the document into a map, empty documents result in an empty map.
+// Line numbers in errors are converted to lines of the rendered file.
+func (d *manifestDocument) decode() (map[string]any, int, error) {
+	var node map[string]any
+	err := yaml.NewDecoder(bytes.NewReader(d.content)).Decode(&node)
+	if err == nil || errors.Is(err, io.EOF) {
+		return node, 0, nil
+	}
+
+	// errors without a line number point to the document start
+	line := 0
+	message := yamlLineRe.ReplaceAllStringFunc(err.Error(), func(match string) string {
+		relative, _ := strconv.Atoi(yamlLineRe.FindStringSubmatch(match)[1])
+		absolute := d.line + relative - 1
+		if line == 0 {
+			line = absolute
+		}
+		return fmt.Sprintf("line %d", absolute)
+	})
+	if line == 0 {
+		line = d.line
+	}
+
+	return nil, line, errors.New(message)
+}
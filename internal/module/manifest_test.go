@@ -0,0 +1,67 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	content := "# Source: module/templates/a.yaml\n" +
+		"---\n" +
+		"kind: ConfigMap\n" +
+		"data:\n" +
+		"  script: |\n" +
+		"    echo a\n" +
+		"    ---\n" +
+		"    echo b\n" +
+		"  value: a---b\n" +
+		"--- # second\n" +
+		"kind: Secret\n"
+
+	docs := splitDocuments(content)
+	require.Len(t, docs, 2)
+	assert.Equal(t, 0, docs[0].index)
+	assert.Equal(t, 2, docs[0].line)
+	assert.Equal(t, 1, docs[1].index)
+	assert.Equal(t, 10, docs[1].line)
+
+	node, _, err := docs[0].decode()
+	require.NoError(t, err)
+	assert.Equal(t, "echo a\n---\necho b\n", node["data"].(map[string]any)["script"])
+}
+
+func TestParseObjectsSkipsBadDocuments(t *testing.T) {
+	files := map[string]string{
+		"module/templates/a.yaml": "---\n" +
+			"apiVersion: v1\n" +
+			"kind: ConfigMap\n" +
+			"metadata:\n" +
+			"  name: good\n" +
+			"---\n" +
+			"apiVersion: v1\n" +
+			"kind: ConfigMap\n" +
+			"metadata:\n" +
+			"  name: bad\n" +
+			"   namespace: broken\n" +
+			"---\n" +
+			"apiVersion: v1\n" +
+			"kind: Secret\n" +
+			"metadata:\n" +
+			"  name: also-good\n",
+	}
+
+	store, manifestErrors := ParseObjects(files)
+	assert.True(t, store.Exists(storage.ResourceIndex{Kind: "ConfigMap", Name: "good"}))
+	assert.True(t, store.Exists(storage.ResourceIndex{Kind: "Secret", Name: "also-good"}))
+	assert.Len(t, store.Storage, 2)
+
+	require.Len(t, manifestErrors, 1)
+	assert.Equal(t, "module/templates/a.yaml", manifestErrors[0].Path)
+	assert.Equal(t, 1, manifestErrors[0].Document)
+	assert.Equal(t, 11, manifestErrors[0].Line)
+	assert.Contains(t, manifestErrors[0].Err.Error(), "line 11")
+}
@@ -8,9 +8,11 @@ import (
 
 	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 
+	"github.com/deckhouse/dmt/internal/helm"
 	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/internal/valuesvalidation"
 )
 
 const (
@@ -18,17 +20,31 @@ const (
 )
 
 type Module struct {
-	name        string
-	namespace   string
-	path        string
-	chart       *chart.Chart
-	objectStore *storage.UnstructuredObjectStore
+	name         string
+	namespace    string
+	path         string
+	definition   *Definition
+	chart        *chart.Chart
+	capabilities *chartutil.Capabilities
+	objectStore  *storage.UnstructuredObjectStore
+	// valuesValidator contains the module and global OpenAPI schemas
+	valuesValidator *valuesvalidation.ValuesValidator
+	// values are composed from the OpenAPI schemas, the module is rendered with them
+	values chartutil.Values
+	// haRenders contains renders with the high availability mode turned on and off, they are done on demand
+	haRenders map[bool]*haRender
+	// manifestErrors contains rendered documents which can't be parsed
+	manifestErrors []ManifestError
+	// undefinedIncludes contains calls of helpers not defined by the chart and library charts
+	undefinedIncludes []helm.UndefinedInclude
+	// hash identifies the module files and rendered objects, it is empty if the cache is disabled
+	hash string
 }
 
 type ModuleList []*Module
 
 func (m *Module) String() string {
-	return fmt.Sprintf("{Name: %s, Namespace: %s, Path: %s}", m.name, m.namespace, m.path)
+	return fmt.Sprintf("{Name: %s, Namespace: %s, Path: %s, KubeVersion: %s}", m.name, m.namespace, m.path, m.GetKubeVersion())
 }
 
 func (m *Module) GetName() string {
@@ -53,6 +69,14 @@ func (m *Module) GetPath() string {
 	return m.path
 }
 
+// GetDefinition returns the parsed module.yaml, nil if the module does not have one.
+func (m *Module) GetDefinition() *Definition {
+	if m == nil {
+		return nil
+	}
+	return m.definition
+}
+
 func (m *Module) GetChart() *chart.Chart {
 	if m == nil {
 		return nil
@@ -60,6 +84,41 @@ func (m *Module) GetChart() *chart.Chart {
 	return m.chart
 }
 
+// GetValuesValidator returns the validator with the module and global OpenAPI schemas.
+func (m *Module) GetValuesValidator() *valuesvalidation.ValuesValidator {
+	if m == nil {
+		return nil
+	}
+	return m.valuesValidator
+}
+
+// GetCapabilities returns capabilities the module was rendered with.
+// Nil means helm default capabilities.
+func (m *Module) GetCapabilities() *chartutil.Capabilities {
+	if m == nil {
+		return nil
+	}
+	return m.capabilities
+}
+
+// GetKubeVersion returns the target Kubernetes version the module was rendered for.
+// Empty string means no target version was configured.
+func (m *Module) GetKubeVersion() string {
+	if m == nil || m.capabilities == nil {
+		return ""
+	}
+	return m.capabilities.KubeVersion.Version
+}
+
+// GetHash returns the hash of the module files and rendered objects.
+// Empty string means the module content is not hashed because the cache is disabled.
+func (m *Module) GetHash() string {
+	if m == nil {
+		return ""
+	}
+	return m.hash
+}
+
 func (m *Module) GetMetadata() *chart.Metadata {
 	if m.chart == nil || m.chart.Metadata == nil {
 		return nil
@@ -75,6 +134,22 @@ func (m *Module) GetObjectStore() *storage.UnstructuredObjectStore {
 	return m.objectStore
 }
 
+// GetManifestErrors returns rendered documents which can't be parsed, they are not in the object store.
+func (m *Module) GetManifestErrors() []ManifestError {
+	if m == nil {
+		return nil
+	}
+	return m.manifestErrors
+}
+
+// GetUndefinedIncludes returns include and template calls of helpers not defined by the chart and library charts.
+func (m *Module) GetUndefinedIncludes() []helm.UndefinedInclude {
+	if m == nil {
+		return nil
+	}
+	return m.undefinedIncludes
+}
+
 func (m *Module) GetStorage() map[storage.ResourceIndex]storage.StoreObject {
 	if m == nil || m.objectStore == nil {
 		return nil
@@ -82,38 +157,60 @@ func (m *Module) GetStorage() map[storage.ResourceIndex]storage.StoreObject {
 	return m.objectStore.Storage
 }
 
-func NewModule(path string) (*Module, error) {
-	name, err := getModuleName(path)
+// NewModule loads the module from path and renders it with caps.
+// If caps is nil, helm default capabilities are used.
+func NewModule(path string, caps *chartutil.Capabilities) (*Module, error) {
+	definition, err := ParseDefinition(path)
 	if err != nil {
 		return nil, err
 	}
-	namespace, err := getNamespace(path)
+	name, namespace, err := resolveIdentity(path, definition)
 	if err != nil {
 		return nil, err
 	}
 	module := &Module{
-		name:      name,
-		namespace: namespace,
-		path:      path,
+		name:         name,
+		namespace:    namespace,
+		path:         path,
+		definition:   definition,
+		capabilities: caps,
+		haRenders:    map[bool]*haRender{true: {}, false: {}},
 	}
 
-	ch, err := loader.Load(path)
+	ch, err := loadChart(path, name)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := addLibraryCharts(ch); err != nil {
+		return nil, err
+	}
+
 	module.chart = ch
+	module.undefinedIncludes = helm.UndefinedIncludes(ch)
+
+	module.valuesValidator, err = valuesvalidation.NewValuesValidator(module.name, module.path)
+	if err != nil {
+		return nil, fmt.Errorf("schemas load: %w", err)
+	}
 
 	values, err := ComposeValuesFromSchemas(module)
 	if err != nil {
 		return nil, err
 	}
-	objectStore := storage.NewUnstructuredObjectStore()
-	err = RunRender(module, values, objectStore)
+	module.values = values
+	module.objectStore, module.manifestErrors, err = RunRender(module, values)
+	if err != nil {
+		if len(module.undefinedIncludes) > 0 {
+			return nil, &UndefinedIncludesError{Includes: module.undefinedIncludes, Err: err}
+		}
+		return nil, err
+	}
+
+	module.hash, err = module.computeHash()
 	if err != nil {
 		return nil, err
 	}
-	module.objectStore = objectStore
 
 	return module, nil
 }
@@ -136,7 +233,7 @@ func getModuleName(path string) (name string, err error) {
 }
 
 func getNamespace(path string) (name string, err error) {
-	content, err := os.ReadFile(filepath.Join(path, ".namespace"))
+	content, err := os.ReadFile(filepath.Join(path, NamespaceFilename))
 	if err != nil {
 		return "", err
 	}
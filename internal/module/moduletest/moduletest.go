@@ -0,0 +1,24 @@
+// Package moduletest provides helpers for tests of linters working with rendered module objects.
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+// ObjectStore puts manifests to a new object store the way rendered module files are parsed,
+// as if they were rendered to templates/test.yaml. The test fails if a document can't be parsed.
+func ObjectStore(t testing.TB, manifests string) *storage.UnstructuredObjectStore {
+	t.Helper()
+
+	store, manifestErrors := module.ParseObjects(map[string]string{"templates/test.yaml": manifests})
+	for i := range manifestErrors {
+		require.NoError(t, &manifestErrors[i])
+	}
+
+	return store
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/mohae/deepcopy"
 	"helm.sh/helm/v3/pkg/chartutil"
 
-	"github.com/deckhouse/dmt/internal/valuesvalidation"
+	"github.com/deckhouse/dmt/internal/helm"
 )
 
 const (
@@ -40,10 +40,10 @@ func applyDigests(digests map[string]any, values any) {
 }
 
 func helmFormatModuleImages(m *Module, rawValues map[string]any) (chartutil.Values, error) {
-	caps := chartutil.DefaultCapabilities
-	vers := []string(caps.APIVersions)
-	vers = append(vers, "autoscaling.k8s.io/v1/VerticalPodAutoscaler")
-	caps.APIVersions = vers
+	caps := m.GetCapabilities()
+	if caps == nil {
+		caps = helm.DefaultCapabilities()
+	}
 
 	digests, err := GetModulesImagesDigests(m.GetPath())
 	if err != nil {
@@ -105,11 +105,7 @@ func getModulesImagesDigestsFromLocalPath(modulePath string) (map[string]any, er
 }
 
 func ComposeValuesFromSchemas(m *Module) (chartutil.Values, error) {
-	valueValidator, err := valuesvalidation.NewValuesValidator(m.GetName(), m.GetPath())
-	if err != nil {
-		return nil, fmt.Errorf("schemas load: %w", err)
-	}
-
+	valueValidator := m.GetValuesValidator()
 	if valueValidator == nil {
 		return nil, nil
 	}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"path"
+	"slices"
+)
+
+// DuplicatePolicy defines how an object rendered twice by a module is reported
+type DuplicatePolicy string
+
+const (
+	DuplicateError DuplicatePolicy = "error"
+	DuplicateWarn  DuplicatePolicy = "warn"
+	DuplicateAllow DuplicatePolicy = "allow"
+)
+
+// DuplicateRule sets the policy for duplicates matching the kind, namespace and name patterns.
+// Patterns use path.Match syntax, an empty pattern matches anything.
+type DuplicateRule struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Policy    DuplicatePolicy
+}
+
+// Duplicate is an object rendered more than once. The store keeps the first object.
+type Duplicate struct {
+	Index ResourceIndex
+	// Paths contains the template path of the kept object and the template path of the duplicate
+	Paths  [2]string
+	Policy DuplicatePolicy
+}
+
+// DefaultDuplicateRules are applied after the configured rules.
+// cert-manager migration renders legacy duplicates of ClusterIssuers and objects in the d8-cert-manager namespace,
+// they are expected in the cluster. Remove the rules after the legacy version is removed.
+var DefaultDuplicateRules = []DuplicateRule{
+	{Kind: "ClusterIssuer", Policy: DuplicateAllow},
+	{Namespace: "d8-cert-manager", Policy: DuplicateAllow},
+}
+
+var duplicateRules = DefaultDuplicateRules
+
+// SetDuplicateRules sets rules for duplicate objects followed by DefaultDuplicateRules, the first matching rule wins.
+// Duplicates not matching any rule are errors.
+func SetDuplicateRules(rules []DuplicateRule) {
+	duplicateRules = slices.Concat(rules, DefaultDuplicateRules)
+}
+
+func duplicatePolicy(index ResourceIndex) DuplicatePolicy {
+	for _, rule := range duplicateRules {
+		if matchPattern(rule.Kind, index.Kind) &&
+			matchPattern(rule.Namespace, index.Namespace) &&
+			matchPattern(rule.Name, index.Name) {
+			return rule.Policy
+		}
+	}
+
+	return DuplicateError
+}
+
+func matchPattern(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	ok, err := path.Match(pattern, value)
+
+	return err == nil && ok
+}
+
+// Duplicates returns objects rendered more than once, except allowed ones, in the rendering order.
+func (s *UnstructuredObjectStore) Duplicates() []Duplicate {
+	return s.duplicates
+}
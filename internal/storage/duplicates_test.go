@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicatePolicies(t *testing.T) {
+	SetDuplicateRules([]DuplicateRule{
+		{Kind: "ClusterIssuer", Policy: DuplicateAllow},
+		{Namespace: "d8-cert-*", Policy: DuplicateWarn},
+		{Namespace: "d8-legacy-*", Policy: DuplicateWarn},
+	})
+	t.Cleanup(func() { SetDuplicateRules(nil) })
+
+	store := NewUnstructuredObjectStore()
+	put := func(path, kind, namespace, name string) {
+		object := map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name, "namespace": namespace},
+		}
+		store.Put(path, object, []byte(path))
+	}
+
+	for _, path := range []string{"templates/a.yaml", "templates/b.yaml"} {
+		put(path, "ClusterIssuer", "", "selfsigned")
+		put(path, "Secret", "d8-cert-manager", "webhook")
+		put(path, "ConfigMap", "d8-system", "config")
+		put(path, "Issuer", "d8-legacy-cert-manager", "selfsigned")
+	}
+
+	assert.Equal(t, []Duplicate{
+		{
+			Index:  ResourceIndex{Kind: "Secret", Name: "webhook", Namespace: "d8-cert-manager"},
+			Paths:  [2]string{"templates/a.yaml", "templates/b.yaml"},
+			Policy: DuplicateWarn,
+		},
+		{
+			Index:  ResourceIndex{Kind: "ConfigMap", Name: "config", Namespace: "d8-system"},
+			Paths:  [2]string{"templates/a.yaml", "templates/b.yaml"},
+			Policy: DuplicateError,
+		},
+		{
+			Index:  ResourceIndex{Kind: "Issuer", Name: "selfsigned", Namespace: "d8-legacy-cert-manager"},
+			Paths:  [2]string{"templates/a.yaml", "templates/b.yaml"},
+			Policy: DuplicateWarn,
+		},
+	}, store.Duplicates())
+	assert.Equal(t, "templates/a.yaml", store.Get(ResourceIndex{Kind: "ConfigMap", Name: "config", Namespace: "d8-system"}).Path)
+}
+
+func TestDefaultDuplicatePolicies(t *testing.T) {
+	SetDuplicateRules([]DuplicateRule{
+		{Kind: "Secret", Namespace: "d8-cert-manager", Policy: DuplicateWarn},
+	})
+	t.Cleanup(func() { SetDuplicateRules(nil) })
+
+	store := NewUnstructuredObjectStore()
+	put := func(path, kind, namespace, name string) {
+		object := map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name, "namespace": namespace},
+		}
+		store.Put(path, object, []byte(path))
+	}
+
+	for _, path := range []string{"templates/a.yaml", "templates/b.yaml"} {
+		put(path, "ClusterIssuer", "", "selfsigned")
+		put(path, "ConfigMap", "d8-cert-manager", "config")
+		put(path, "Secret", "d8-cert-manager", "webhook")
+	}
+
+	assert.Equal(t, []Duplicate{
+		{
+			Index:  ResourceIndex{Kind: "Secret", Name: "webhook", Namespace: "d8-cert-manager"},
+			Paths:  [2]string{"templates/a.yaml", "templates/b.yaml"},
+			Policy: DuplicateWarn,
+		},
+	}, store.Duplicates())
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// podTemplatePaths contains paths to the pod template of every workload kind
+var podTemplatePaths = map[string][]string{
+	"Deployment":            {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+	"PodTemplate":           {"template"},
+}
+
+type podTemplate struct {
+	once     sync.Once
+	template *v1.PodTemplateSpec
+	err      error
+}
+
+// PodTemplate returns the pod metadata and spec of a Pod or a workload object, nil for other kinds.
+// The object is converted once, the returned template is shared and must not be modified.
+func (s *StoreObject) PodTemplate() (*v1.PodTemplateSpec, error) {
+	if s.podTemplate == nil {
+		return convertPodTemplate(&s.Unstructured)
+	}
+
+	s.podTemplate.once.Do(func() {
+		s.podTemplate.template, s.podTemplate.err = convertPodTemplate(&s.Unstructured)
+	})
+
+	return s.podTemplate.template, s.podTemplate.err
+}
+
+func convertPodTemplate(object *unstructured.Unstructured) (*v1.PodTemplateSpec, error) {
+	kind := object.GetKind()
+	content := object.UnstructuredContent()
+
+	var templateContent map[string]any
+	if kind == "Pod" {
+		templateContent = map[string]any{
+			"metadata": content["metadata"],
+			"spec":     content["spec"],
+		}
+	} else {
+		path, ok := podTemplatePaths[kind]
+		if !ok {
+			return nil, nil
+		}
+
+		field, found, err := unstructured.NestedFieldNoCopy(content, path...)
+		if err != nil {
+			return nil, fmt.Errorf("convert Unstructured to %s failed: %w", kind, err)
+		}
+		if !found {
+			return &v1.PodTemplateSpec{}, nil
+		}
+
+		templateContent, ok = field.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("convert Unstructured to %s failed: pod template is %T, not an object", kind, field)
+		}
+	}
+
+	template := new(v1.PodTemplateSpec)
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateContent, template)
+	if err != nil {
+		return nil, fmt.Errorf("convert Unstructured to %s failed: %w", kind, err)
+	}
+
+	return template, nil
+}
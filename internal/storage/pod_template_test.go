@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func putObject(t *testing.T, store *UnstructuredObjectStore, manifest string) StoreObject {
+	t.Helper()
+
+	var object map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(manifest), &object))
+	store.Put("templates/test.yaml", object, []byte(manifest))
+
+	for _, storeObject := range store.Storage {
+		if storeObject.Hash == NewSHA256([]byte(manifest)) {
+			return storeObject
+		}
+	}
+	require.FailNow(t, "object is not stored")
+
+	return StoreObject{}
+}
+
+func TestPodTemplate(t *testing.T) {
+	store := NewUnstructuredObjectStore()
+
+	tests := []struct {
+		manifest  string
+		container string
+		label     string
+	}{
+		{
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  labels: {app: pod}
+spec:
+  containers: [{name: pod}]
+`,
+			container: "pod",
+			label:     "pod",
+		},
+		{
+			manifest: `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: rs
+spec:
+  template:
+    metadata:
+      labels: {app: rs}
+    spec:
+      initContainers: [{name: init}]
+      containers: [{name: rs}]
+`,
+			container: "rs",
+			label:     "rs",
+		},
+		{
+			manifest: `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cronjob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          labels: {app: cronjob}
+        spec:
+          containers: [{name: cronjob}]
+`,
+			container: "cronjob",
+			label:     "cronjob",
+		},
+	}
+
+	for _, tt := range tests {
+		object := putObject(t, store, tt.manifest)
+
+		template, err := object.PodTemplate()
+		require.NoError(t, err)
+		require.NotNil(t, template)
+		require.Len(t, template.Spec.Containers, 1)
+		assert.Equal(t, tt.container, template.Spec.Containers[0].Name)
+		assert.Equal(t, tt.label, template.Labels["app"])
+
+		stored := store.Get(GetResourceIndex(object))
+		again, err := stored.PodTemplate()
+		require.NoError(t, err)
+		assert.Same(t, template, again, "pod template must be converted once")
+	}
+
+	configMap := putObject(t, store, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n")
+	template, err := configMap.PodTemplate()
+	require.NoError(t, err)
+	assert.Nil(t, template)
+
+	invalid := putObject(t, store, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: invalid\nspec:\n  template:\n    spec:\n      containers: invalid\n")
+	_, err = invalid.PodTemplate()
+	require.Error(t, err)
+}
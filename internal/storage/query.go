@@ -0,0 +1,440 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// storeIndexes speed up queries to the object store.
+// Kind, namespace, label and target indexes are filled on Put,
+// the references index requires pod templates conversion and is built on the first query.
+type storeIndexes struct {
+	kinds      map[string][]ResourceIndex
+	namespaces map[string][]ResourceIndex
+	// labels contains objects having the key=value label
+	labels map[string][]ResourceIndex
+	// targets contains objects pointing to the key by ownerReferences, spec.targetRef or spec.scaleTargetRef
+	targets map[ResourceIndex][]ResourceIndex
+
+	referencesMu sync.Mutex
+	// references contains objects using the ConfigMap, Secret, ServiceAccount, PersistentVolumeClaim or PriorityClass key
+	references map[ResourceIndex][]ResourceIndex
+}
+
+func newStoreIndexes() *storeIndexes {
+	return &storeIndexes{
+		kinds:      make(map[string][]ResourceIndex),
+		namespaces: make(map[string][]ResourceIndex),
+		labels:     make(map[string][]ResourceIndex),
+		targets:    make(map[ResourceIndex][]ResourceIndex),
+	}
+}
+
+func (i *storeIndexes) add(index ResourceIndex, object *StoreObject) {
+	i.kinds[index.Kind] = append(i.kinds[index.Kind], index)
+	i.namespaces[index.Namespace] = append(i.namespaces[index.Namespace], index)
+
+	for key, value := range object.Unstructured.GetLabels() {
+		i.labels[key+"="+value] = append(i.labels[key+"="+value], index)
+	}
+
+	for _, target := range targetRefs(object) {
+		i.targets[target] = append(i.targets[target], index)
+	}
+
+	i.referencesMu.Lock()
+	i.references = nil
+	i.referencesMu.Unlock()
+}
+
+// ByKind returns objects of the kinds sorted by their resource index.
+func (s *UnstructuredObjectStore) ByKind(kinds ...string) []StoreObject {
+	var indexes []ResourceIndex
+	for _, kind := range kinds {
+		indexes = append(indexes, s.indexes.kinds[kind]...)
+	}
+
+	return s.objects(indexes)
+}
+
+// ByAPIVersionKind returns objects of the apiVersion and kind.
+func (s *UnstructuredObjectStore) ByAPIVersionKind(apiVersion, kind string) []StoreObject {
+	return slices.DeleteFunc(s.ByKind(kind), func(object StoreObject) bool {
+		return object.Unstructured.GetAPIVersion() != apiVersion
+	})
+}
+
+// ByNamespace returns objects of the namespace, empty namespace means cluster scoped objects.
+func (s *UnstructuredObjectStore) ByNamespace(namespace string) []StoreObject {
+	return s.objects(s.indexes.namespaces[namespace])
+}
+
+// ByLabels returns objects with labels matching the selector.
+// Candidates are taken from the label index by the selector equality requirements.
+func (s *UnstructuredObjectStore) ByLabels(selector labels.Selector) []StoreObject {
+	candidates, found := s.labelCandidates(selector)
+	if !found {
+		candidates = make([]ResourceIndex, 0, len(s.Storage))
+		for index := range s.Storage {
+			candidates = append(candidates, index)
+		}
+	}
+
+	return slices.DeleteFunc(s.objects(candidates), func(object StoreObject) bool {
+		return !selector.Matches(labels.Set(object.Unstructured.GetLabels()))
+	})
+}
+
+// labelCandidates returns objects having labels required by `=`, `==` and `in` requirements of the selector.
+// It returns false if the selector has no such requirements.
+func (s *UnstructuredObjectStore) labelCandidates(selector labels.Selector) ([]ResourceIndex, bool) {
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return nil, true
+	}
+
+	var (
+		candidates []ResourceIndex
+		found      bool
+	)
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+		default:
+			continue
+		}
+
+		var matching []ResourceIndex
+		for _, value := range requirement.Values().UnsortedList() {
+			matching = append(matching, s.indexes.labels[requirement.Key()+"="+value]...)
+		}
+
+		if !found {
+			candidates, found = matching, true
+			continue
+		}
+
+		matchingSet := make(map[ResourceIndex]struct{}, len(matching))
+		for _, index := range matching {
+			matchingSet[index] = struct{}{}
+		}
+		candidates = slices.DeleteFunc(candidates, func(index ResourceIndex) bool {
+			_, ok := matchingSet[index]
+			return !ok
+		})
+	}
+
+	return candidates, found
+}
+
+// ByTarget returns objects pointing to the target by ownerReferences, spec.targetRef (e.g. VerticalPodAutoscaler)
+// or spec.scaleTargetRef (e.g. HorizontalPodAutoscaler).
+func (s *UnstructuredObjectStore) ByTarget(target ResourceIndex) []StoreObject {
+	return s.objects(s.indexes.targets[target])
+}
+
+// PodControllers returns Pods and workloads of the namespace with pod template labels matching the selector.
+func (s *UnstructuredObjectStore) PodControllers(namespace string, selector labels.Selector) []StoreObject {
+	return slices.DeleteFunc(s.ByNamespace(namespace), func(object StoreObject) bool {
+		// PodTemplate objects do not run pods
+		if object.Unstructured.GetKind() == "PodTemplate" {
+			return true
+		}
+
+		template, err := object.PodTemplate()
+		if err != nil || template == nil {
+			return true
+		}
+
+		return !selector.Matches(labels.Set(template.Labels))
+	})
+}
+
+// SelectedPods returns Pods and workloads with pods selected by a Service, PodDisruptionBudget, NetworkPolicy or PodMonitor.
+func (s *UnstructuredObjectStore) SelectedPods(object StoreObject) ([]StoreObject, error) {
+	selector, err := podSelector(&object.Unstructured)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", object.Identity(), err)
+	}
+
+	var result []StoreObject
+	for _, namespace := range s.selectedNamespaces(&object.Unstructured) {
+		result = append(result, s.PodControllers(namespace, selector)...)
+	}
+
+	return result, nil
+}
+
+// SelectedServices returns Services selected by a ServiceMonitor.
+func (s *UnstructuredObjectStore) SelectedServices(object StoreObject) ([]StoreObject, error) {
+	if object.Unstructured.GetKind() != "ServiceMonitor" {
+		return nil, fmt.Errorf("%s: kind %s does not select services", object.Identity(), object.Unstructured.GetKind())
+	}
+
+	selector, err := labelSelector(&object.Unstructured, "spec", "selector")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", object.Identity(), err)
+	}
+
+	namespaces := s.selectedNamespaces(&object.Unstructured)
+	return slices.DeleteFunc(s.ByKind("Service"), func(service StoreObject) bool {
+		return !slices.Contains(namespaces, service.Unstructured.GetNamespace()) ||
+			!selector.Matches(labels.Set(service.Unstructured.GetLabels()))
+	}), nil
+}
+
+// selectedNamespaces returns namespaces the object selects pods or services in: the object namespace,
+// or namespaces of the ServiceMonitor and PodMonitor namespaceSelector, `any: true` selects all namespaces of the store.
+func (s *UnstructuredObjectStore) selectedNamespaces(object *unstructured.Unstructured) []string {
+	switch object.GetKind() {
+	case "ServiceMonitor", "PodMonitor":
+	default:
+		return []string{object.GetNamespace()}
+	}
+
+	if anyNamespace, _, _ := unstructured.NestedBool(object.Object, "spec", "namespaceSelector", "any"); anyNamespace {
+		namespaces := make([]string, 0, len(s.indexes.namespaces))
+		for namespace := range s.indexes.namespaces {
+			if namespace != "" {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		slices.Sort(namespaces)
+		return namespaces
+	}
+
+	if names, found, _ := unstructured.NestedStringSlice(object.Object, "spec", "namespaceSelector", "matchNames"); found {
+		return names
+	}
+
+	return []string{object.GetNamespace()}
+}
+
+// ReferencedBy returns objects using the ConfigMap, Secret, ServiceAccount, PersistentVolumeClaim or PriorityClass:
+// workloads mounting or reading it in the pod template and bindings with the ServiceAccount subject.
+func (s *UnstructuredObjectStore) ReferencedBy(target ResourceIndex) []StoreObject {
+	s.indexes.referencesMu.Lock()
+	if s.indexes.references == nil {
+		s.indexes.references = make(map[ResourceIndex][]ResourceIndex)
+		for index, object := range s.Storage {
+			for _, ref := range objectReferences(&object) {
+				s.indexes.references[ref] = append(s.indexes.references[ref], index)
+			}
+		}
+	}
+	indexes := s.indexes.references[target]
+	s.indexes.referencesMu.Unlock()
+
+	return s.objects(indexes)
+}
+
+// objects returns unique objects by indexes in a stable order.
+func (s *UnstructuredObjectStore) objects(indexes []ResourceIndex) []StoreObject {
+	indexes = slices.Clone(indexes)
+	slices.SortFunc(indexes, func(a, b ResourceIndex) int {
+		return strings.Compare(a.AsString(), b.AsString())
+	})
+	indexes = slices.Compact(indexes)
+
+	result := make([]StoreObject, 0, len(indexes))
+	for _, index := range indexes {
+		if object, ok := s.Storage[index]; ok {
+			result = append(result, object)
+		}
+	}
+
+	return result
+}
+
+// podSelector returns the selector of pods the object applies to.
+// Service without selector selects nothing, NetworkPolicy with empty podSelector selects all pods.
+func podSelector(object *unstructured.Unstructured) (labels.Selector, error) {
+	switch object.GetKind() {
+	case "Service":
+		selector, _, err := unstructured.NestedStringMap(object.Object, "spec", "selector")
+		if err != nil {
+			return nil, err
+		}
+		if len(selector) == 0 {
+			return labels.Nothing(), nil
+		}
+		return labels.SelectorFromSet(selector), nil
+	case "PodDisruptionBudget", "PodMonitor":
+		return labelSelector(object, "spec", "selector")
+	case "NetworkPolicy":
+		return labelSelector(object, "spec", "podSelector")
+	default:
+		return nil, fmt.Errorf("kind %s does not select pods", object.GetKind())
+	}
+}
+
+func labelSelector(object *unstructured.Unstructured, fields ...string) (labels.Selector, error) {
+	content, found, err := unstructured.NestedMap(object.Object, fields...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return labels.Nothing(), nil
+	}
+
+	selector := new(metav1.LabelSelector)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, selector); err != nil {
+		return nil, err
+	}
+
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// targetRefs returns objects the object points to by ownerReferences, spec.targetRef or spec.scaleTargetRef.
+func targetRefs(object *StoreObject) []ResourceIndex {
+	namespace := object.Unstructured.GetNamespace()
+
+	var result []ResourceIndex
+	for _, owner := range object.Unstructured.GetOwnerReferences() {
+		result = append(result, ResourceIndex{Kind: owner.Kind, Name: owner.Name, Namespace: namespace})
+	}
+
+	for _, field := range []string{"targetRef", "scaleTargetRef"} {
+		ref, found, err := unstructured.NestedStringMap(object.Unstructured.Object, "spec", field)
+		if err != nil || !found {
+			continue
+		}
+		result = append(result, ResourceIndex{Kind: ref["kind"], Name: ref["name"], Namespace: namespace})
+	}
+
+	return result
+}
+
+// objectReferences returns objects the object uses: ServiceAccount subjects of bindings and references of the pod template.
+func objectReferences(object *StoreObject) []ResourceIndex {
+	namespace := object.Unstructured.GetNamespace()
+
+	switch object.Unstructured.GetKind() {
+	case "RoleBinding", "ClusterRoleBinding":
+		subjects, _, _ := unstructured.NestedSlice(object.Unstructured.Object, "subjects")
+
+		var result []ResourceIndex
+		for _, subject := range subjects {
+			s, ok := subject.(map[string]any)
+			if !ok || s["kind"] != "ServiceAccount" {
+				continue
+			}
+			name, _ := s["name"].(string)
+			ns, _ := s["namespace"].(string)
+			if ns == "" {
+				ns = namespace
+			}
+			result = append(result, ResourceIndex{Kind: "ServiceAccount", Name: name, Namespace: ns})
+		}
+
+		return result
+	}
+
+	references, _ := object.PodReferences()
+	result := make([]ResourceIndex, 0, len(references))
+	for _, reference := range references {
+		result = append(result, reference.ResourceIndex)
+	}
+
+	return result
+}
+
+// Reference is an object the pod template refers to
+type Reference struct {
+	ResourceIndex
+	// Optional references may point to missing objects, e.g. ConfigMap volumes with `optional: true`
+	Optional bool
+}
+
+// PodReferences returns ServiceAccounts, ConfigMaps, Secrets, PersistentVolumeClaims and the PriorityClass
+// the pod template of the object refers to. It returns nil for objects without a pod template.
+func (s *StoreObject) PodReferences() ([]Reference, error) {
+	template, err := s.PodTemplate()
+	if err != nil || template == nil {
+		return nil, err
+	}
+
+	return podReferences(s.Unstructured.GetNamespace(), &template.Spec), nil
+}
+
+//nolint:gocyclo // every reference field is checked
+func podReferences(namespace string, spec *v1.PodSpec) []Reference {
+	var result []Reference
+	add := func(kind, name string, optional *bool) {
+		if name == "" {
+			return
+		}
+		index := ResourceIndex{Kind: kind, Name: name, Namespace: namespace}
+		if kind == "PriorityClass" {
+			index.Namespace = ""
+		}
+		result = append(result, Reference{ResourceIndex: index, Optional: optional != nil && *optional})
+	}
+
+	if spec.ServiceAccountName != "" {
+		add("ServiceAccount", spec.ServiceAccountName, nil)
+	} else {
+		add("ServiceAccount", spec.DeprecatedServiceAccount, nil)
+	}
+
+	add("PriorityClass", spec.PriorityClassName, nil)
+
+	for _, secret := range spec.ImagePullSecrets {
+		add("Secret", secret.Name, nil)
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			add("ConfigMap", volume.ConfigMap.Name, volume.ConfigMap.Optional)
+		}
+		if volume.Secret != nil {
+			add("Secret", volume.Secret.SecretName, volume.Secret.Optional)
+		}
+		if volume.PersistentVolumeClaim != nil {
+			add("PersistentVolumeClaim", volume.PersistentVolumeClaim.ClaimName, nil)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add("ConfigMap", source.ConfigMap.Name, source.ConfigMap.Optional)
+				}
+				if source.Secret != nil {
+					add("Secret", source.Secret.Name, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				add("ConfigMap", envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional)
+			}
+			if envFrom.SecretRef != nil {
+				add("Secret", envFrom.SecretRef.Name, envFrom.SecretRef.Optional)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				add("ConfigMap", env.ValueFrom.ConfigMapKeyRef.Name, env.ValueFrom.ConfigMapKeyRef.Optional)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				add("Secret", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Optional)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const queryManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: d8-test
+  labels: {module: test}
+spec:
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      serviceAccountName: web
+      volumes:
+      - name: config
+        configMap: {name: web-config}
+      containers:
+      - name: web
+        envFrom:
+        - secretRef: {name: web-secret}
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+  namespace: d8-test
+spec:
+  template:
+    metadata:
+      labels: {app: agent}
+    spec:
+      containers:
+      - name: agent
+        env:
+        - name: TOKEN
+          valueFrom:
+            secretKeyRef: {name: web-secret, key: token}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: d8-test
+  labels: {app: web}
+spec:
+  selector: {app: web}
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: all
+  namespace: d8-test
+spec:
+  selector: {}
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: agent
+  namespace: d8-test
+spec:
+  podSelector:
+    matchLabels: {app: agent}
+---
+apiVersion: autoscaling.k8s.io/v1
+kind: VerticalPodAutoscaler
+metadata:
+  name: web
+  namespace: d8-test
+spec:
+  targetRef: {apiVersion: apps/v1, kind: Deployment, name: web}
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: web
+  namespace: d8-monitoring
+spec:
+  namespaceSelector:
+    matchNames: [d8-test]
+  selector:
+    matchLabels: {app: web}
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PodMonitor
+metadata:
+  name: agent
+  namespace: d8-monitoring
+spec:
+  namespaceSelector: {any: true}
+  selector:
+    matchLabels: {app: agent}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: web
+subjects:
+- kind: ServiceAccount
+  name: web
+  namespace: d8-test
+`
+
+func splitManifests(manifests string) []string {
+	return strings.Split(strings.TrimSpace(manifests), "\n---\n")
+}
+
+func names(objects []StoreObject) []string {
+	result := make([]string, 0, len(objects))
+	for _, object := range objects {
+		result = append(result, object.Unstructured.GetKind()+"/"+object.Unstructured.GetName())
+	}
+	return result
+}
+
+func TestStoreQueries(t *testing.T) {
+	store := NewUnstructuredObjectStore()
+	for _, manifest := range splitManifests(queryManifests) {
+		putObject(t, store, manifest)
+	}
+
+	assert.Equal(t, []string{"DaemonSet/agent", "Deployment/web"}, names(store.ByKind("Deployment", "DaemonSet")))
+	assert.Equal(t, []string{"Deployment/web"}, names(store.ByAPIVersionKind("apps/v1", "Deployment")))
+	assert.Empty(t, store.ByAPIVersionKind("extensions/v1beta1", "Deployment"))
+	assert.Equal(t, []string{"ClusterRoleBinding/web"}, names(store.ByNamespace("")))
+	assert.Equal(t, []string{"Deployment/web"}, names(store.ByLabels(labels.SelectorFromSet(labels.Set{"module": "test"}))))
+	for selector, expected := range map[string][]string{
+		"app in (web, agent)":      {"Service/web"},
+		"app=web,module!=test":     {"Service/web"},
+		"app=web,module=test":      {},
+		"module":                   {"Deployment/web"},
+		"module=test,app notin ()": {"Deployment/web"},
+	} {
+		parsed, err := labels.Parse(selector)
+		require.NoError(t, err)
+		assert.Equal(t, expected, names(store.ByLabels(parsed)), selector)
+	}
+	assert.Empty(t, store.ByLabels(labels.Nothing()))
+
+	target := ResourceIndex{Kind: "Deployment", Name: "web", Namespace: "d8-test"}
+	assert.Equal(t, []string{"VerticalPodAutoscaler/web"}, names(store.ByTarget(target)))
+
+	for name, expected := range map[ResourceIndex][]string{
+		{Kind: "Service", Name: "web", Namespace: "d8-test"}:               {"Deployment/web"},
+		{Kind: "PodDisruptionBudget", Name: "all", Namespace: "d8-test"}:   {"DaemonSet/agent", "Deployment/web"},
+		{Kind: "NetworkPolicy", Name: "agent", Namespace: "d8-test"}:       {"DaemonSet/agent"},
+		{Kind: "PodMonitor", Name: "agent", Namespace: "d8-monitoring"}:    {"DaemonSet/agent"},
+		{Kind: "VerticalPodAutoscaler", Name: "web", Namespace: "d8-test"}: nil,
+	} {
+		pods, err := store.SelectedPods(store.Get(name))
+		if expected == nil {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, expected, names(pods), name.AsString())
+	}
+
+	services, err := store.SelectedServices(store.Get(ResourceIndex{Kind: "ServiceMonitor", Name: "web", Namespace: "d8-monitoring"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Service/web"}, names(services))
+
+	assert.Equal(t, []string{"DaemonSet/agent", "Deployment/web"},
+		names(store.ReferencedBy(ResourceIndex{Kind: "Secret", Name: "web-secret", Namespace: "d8-test"})))
+	assert.Equal(t, []string{"Deployment/web"},
+		names(store.ReferencedBy(ResourceIndex{Kind: "ConfigMap", Name: "web-config", Namespace: "d8-test"})))
+	assert.Equal(t, []string{"ClusterRoleBinding/web", "Deployment/web"},
+		names(store.ReferencedBy(ResourceIndex{Kind: "ServiceAccount", Name: "web", Namespace: "d8-test"})))
+}
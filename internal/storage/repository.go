@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+)
+
+// ModuleObject is an object rendered by a module
+type ModuleObject struct {
+	Module string
+	StoreObject
+}
+
+// RepositoryStore aggregates objects rendered by all linted modules.
+// Modules rendered for different target Kubernetes versions must be aggregated in separate stores.
+type RepositoryStore struct {
+	kubeVersion string
+	// modules contains names and namespaces of modules by their paths
+	modules map[string]repositoryModule
+	objects map[ResourceIndex][]ModuleObject
+}
+
+type repositoryModule struct {
+	name      string
+	namespace string
+}
+
+func NewRepositoryStore(kubeVersion string) *RepositoryStore {
+	return &RepositoryStore{
+		kubeVersion: kubeVersion,
+		modules:     make(map[string]repositoryModule),
+		objects:     make(map[ResourceIndex][]ModuleObject),
+	}
+}
+
+// KubeVersion returns the target Kubernetes version modules are rendered for.
+func (r *RepositoryStore) KubeVersion() string {
+	return r.kubeVersion
+}
+
+// Add adds objects rendered by the module located in modulePath.
+func (r *RepositoryStore) Add(moduleName, modulePath, namespace string, store *UnstructuredObjectStore) {
+	r.modules[modulePath] = repositoryModule{name: moduleName, namespace: namespace}
+	if store == nil {
+		return
+	}
+
+	for index, object := range store.Storage {
+		r.objects[index] = append(r.objects[index], ModuleObject{Module: moduleName, StoreObject: object})
+	}
+}
+
+// ModulesByNamespace returns sorted names of modules deploying to the namespace.
+func (r *RepositoryStore) ModulesByNamespace(namespace string) []string {
+	var result []string
+	for _, module := range r.modules {
+		if module.namespace == namespace {
+			result = append(result, module.name)
+		}
+	}
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// Modules returns sorted names of aggregated modules.
+func (r *RepositoryStore) Modules() []string {
+	result := make([]string, 0, len(r.modules))
+	for _, module := range r.modules {
+		result = append(result, module.name)
+	}
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// ModulePaths returns sorted paths of aggregated modules with the name.
+// Several paths mean different modules use the same name.
+func (r *RepositoryStore) ModulePaths(moduleName string) []string {
+	var result []string
+	for path, module := range r.modules {
+		if module.name == moduleName {
+			result = append(result, path)
+		}
+	}
+	slices.Sort(result)
+
+	return result
+}
+
+// Get returns objects with the index rendered by all modules.
+func (r *RepositoryStore) Get(index ResourceIndex) []ModuleObject {
+	return r.objects[index]
+}
+
+func (r *RepositoryStore) Exists(index ResourceIndex) bool {
+	return len(r.objects[index]) > 0
+}
+
+// Indexes returns indexes of all objects in a stable order.
+func (r *RepositoryStore) Indexes() []ResourceIndex {
+	result := make([]ResourceIndex, 0, len(r.objects))
+	for index := range r.objects {
+		result = append(result, index)
+	}
+	slices.SortFunc(result, func(a, b ResourceIndex) int {
+		return strings.Compare(a.AsString(), b.AsString())
+	})
+
+	return result
+}
+
+// ByKind returns objects of the kinds rendered by all modules in a stable order.
+func (r *RepositoryStore) ByKind(kinds ...string) []ModuleObject {
+	var result []ModuleObject
+	for _, index := range r.Indexes() {
+		if slices.Contains(kinds, index.Kind) {
+			result = append(result, r.objects[index]...)
+		}
+	}
+
+	return result
+}
@@ -3,24 +3,12 @@ package storage
 import (
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"os"
+	"path"
 	"strings"
 
-	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-)
-
-const (
-	deploymentString  = "Deployment"
-	daemonSetString   = "DaemonSet"
-	statefulSetString = "StatefulSet"
-	podString         = "Pod"
-	jobString         = "Job"
-	cronJobString     = "CronJob"
-	replicaSetString  = "ReplicaSet"
 )
 
 type ResourceIndex struct {
@@ -37,10 +25,25 @@ func (g *ResourceIndex) AsString() string {
 	return g.Namespace + "/" + g.Kind + "/" + g.Name
 }
 
+// MatchesAny reports whether AsString of the index matches any of path.Match glob patterns
+func (g *ResourceIndex) MatchesAny(patterns []string) bool {
+	name := g.AsString()
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 type StoreObject struct {
 	Path         string
 	Hash         string
 	Unstructured unstructured.Unstructured
+
+	// podTemplate is shared by copies of the object to convert the pod template once
+	podTemplate *podTemplate
 }
 
 func GetResourceIndex(object StoreObject) ResourceIndex {
@@ -51,272 +54,6 @@ func GetResourceIndex(object StoreObject) ResourceIndex {
 	}
 }
 
-func (s *StoreObject) GetInitContainers() ([]v1.Container, error) {
-	var containers []v1.Container
-	converter := runtime.DefaultUnstructuredConverter
-
-	switch s.Unstructured.GetKind() {
-	case deploymentString:
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Deployment failed: %w", err)
-		}
-
-		containers = deployment.Spec.Template.Spec.InitContainers
-	case daemonSetString:
-		daemonSet := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), daemonSet)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to DaemonSet failed: %w", err)
-		}
-
-		containers = daemonSet.Spec.Template.Spec.InitContainers
-	case statefulSetString:
-		statefulSet := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), statefulSet)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to StatefulSet failed: %w", err)
-		}
-
-		containers = statefulSet.Spec.Template.Spec.InitContainers
-	case podString:
-		pod := new(v1.Pod)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), pod)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Pod failed: %w", err)
-		}
-
-		containers = pod.Spec.InitContainers
-	case jobString:
-		job := new(batchv1.Job)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), job)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Job failed: %w", err)
-		}
-
-		containers = job.Spec.Template.Spec.InitContainers
-	case cronJobString:
-		cronJob := new(batchv1.CronJob)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), cronJob)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to CronJob failed: %w", err)
-		}
-
-		containers = cronJob.Spec.JobTemplate.Spec.Template.Spec.InitContainers
-	}
-	return containers, nil
-}
-
-func (s *StoreObject) GetContainers() ([]v1.Container, error) {
-	var containers []v1.Container
-	converter := runtime.DefaultUnstructuredConverter
-
-	switch s.Unstructured.GetKind() {
-	case deploymentString:
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Deployment failed: %w", err)
-		}
-
-		containers = deployment.Spec.Template.Spec.Containers
-	case daemonSetString:
-		daemonSet := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), daemonSet)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to DaemonSet failed: %w", err)
-		}
-
-		containers = daemonSet.Spec.Template.Spec.Containers
-	case statefulSetString:
-		statefulSet := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), statefulSet)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to StatefulSet failed: %w", err)
-		}
-
-		containers = statefulSet.Spec.Template.Spec.Containers
-	case podString:
-		pod := new(v1.Pod)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), pod)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Pod failed: %w", err)
-		}
-
-		containers = pod.Spec.Containers
-	case jobString:
-		job := new(batchv1.Job)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), job)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to Job failed: %w", err)
-		}
-
-		containers = job.Spec.Template.Spec.Containers
-	case cronJobString:
-		cronJob := new(batchv1.CronJob)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), cronJob)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to CronJob failed: %w", err)
-		}
-
-		containers = cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
-	case replicaSetString:
-		replicaSet := new(appsv1.ReplicaSet)
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), replicaSet)
-		if err != nil {
-			return []v1.Container{}, fmt.Errorf("convert Unstructured to ReplicaSet failed: %w", err)
-		}
-
-		containers = replicaSet.Spec.Template.Spec.Containers
-	}
-	return containers, nil
-}
-
-func (s *StoreObject) GetPodSecurityContext() (*v1.PodSecurityContext, error) {
-	converter := runtime.DefaultUnstructuredConverter
-
-	switch s.Unstructured.GetKind() {
-	case deploymentString:
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to Deployment failed: %w", err)
-		}
-
-		return deployment.Spec.Template.Spec.SecurityContext, nil
-	case daemonSetString:
-		daemonSet := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), daemonSet)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to DaemonSet failed: %w", err)
-		}
-
-		return daemonSet.Spec.Template.Spec.SecurityContext, nil
-	case statefulSetString:
-		statefulSet := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), statefulSet)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to StatefulSet failed: %w", err)
-		}
-
-		return statefulSet.Spec.Template.Spec.SecurityContext, nil
-	case podString:
-		pod := new(v1.Pod)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), pod)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to Pod failed: %w", err)
-		}
-
-		return pod.Spec.SecurityContext, nil
-	case jobString:
-		job := new(batchv1.Job)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), job)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to Job failed: %w", err)
-		}
-
-		return job.Spec.Template.Spec.SecurityContext, nil
-	case cronJobString:
-		cronJob := new(batchv1.CronJob)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), cronJob)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to CronJob failed: %w", err)
-		}
-
-		return cronJob.Spec.JobTemplate.Spec.Template.Spec.SecurityContext, nil
-	case replicaSetString:
-		replicaSet := new(appsv1.ReplicaSet)
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), replicaSet)
-		if err != nil {
-			return nil, fmt.Errorf("convert Unstructured to ReplicaSet failed: %w", err)
-		}
-
-		return replicaSet.Spec.Template.Spec.SecurityContext, nil
-	}
-	return nil, nil
-}
-
-func (s *StoreObject) IsHostNetwork() (bool, error) {
-	converter := runtime.DefaultUnstructuredConverter
-
-	switch s.Unstructured.GetKind() {
-	case deploymentString:
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to Deployment failed: %w", err)
-		}
-
-		return deployment.Spec.Template.Spec.HostNetwork, nil
-	case daemonSetString:
-		daemonSet := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), daemonSet)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to DaemonSet failed: %w", err)
-		}
-
-		return daemonSet.Spec.Template.Spec.HostNetwork, nil
-	case statefulSetString:
-		statefulSet := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), statefulSet)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to StatefulSet failed: %w", err)
-		}
-
-		return statefulSet.Spec.Template.Spec.HostNetwork, nil
-	case podString:
-		pod := new(v1.Pod)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), pod)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to Pod failed: %w", err)
-		}
-
-		return pod.Spec.HostNetwork, nil
-	case jobString:
-		job := new(batchv1.Job)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), job)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to Job failed: %w", err)
-		}
-
-		return job.Spec.Template.Spec.HostNetwork, nil
-	case cronJobString:
-		cronJob := new(batchv1.CronJob)
-
-		err := converter.FromUnstructured(s.Unstructured.UnstructuredContent(), cronJob)
-		if err != nil {
-			return false, fmt.Errorf("convert Unstructured to CronJob failed: %w", err)
-		}
-
-		return cronJob.Spec.JobTemplate.Spec.Template.Spec.HostNetwork, nil
-	}
-	return false, nil
-}
-
 func (s *StoreObject) ShortPath() string {
 	elements := strings.Split(s.Path, string(os.PathSeparator))
 	if len(elements) == 0 {
@@ -339,30 +76,40 @@ func (s *StoreObject) Identity() string {
 
 type UnstructuredObjectStore struct {
 	Storage map[ResourceIndex]StoreObject
+
+	indexes    *storeIndexes
+	duplicates []Duplicate
 }
 
 func NewUnstructuredObjectStore() *UnstructuredObjectStore {
-	return &UnstructuredObjectStore{Storage: make(map[ResourceIndex]StoreObject)}
+	return &UnstructuredObjectStore{
+		Storage: make(map[ResourceIndex]StoreObject),
+		indexes: newStoreIndexes(),
+	}
 }
 
-func (s *UnstructuredObjectStore) Put(path string, object map[string]any, raw []byte) error {
+// Put adds the object rendered in the path. Only the first object with the same resource index is kept,
+// duplicates are collected.
+func (s *UnstructuredObjectStore) Put(path string, object map[string]any, raw []byte) {
 	var u unstructured.Unstructured
-	u.SetUnstructuredContent(object)
+	u.SetUnstructuredContent(jsonNumbers(object).(map[string]any))
 
-	storeObject := StoreObject{Path: path, Unstructured: u, Hash: NewSHA256(raw)}
+	storeObject := StoreObject{Path: path, Unstructured: u, Hash: NewSHA256(raw), podTemplate: new(podTemplate)}
 
 	index := GetResourceIndex(storeObject)
-	if _, ok := s.Storage[index]; ok {
-		// for cert-manager migration we have duplicated resources for legacy version
-		// it's ok for cluster but is not expected by tests. Remove it after legacy version will be removed
-		if strings.Contains(index.AsString(), "ClusterIssuer") || strings.HasPrefix(index.AsString(), "d8-cert-manager") {
-			return nil
-		}
-		return fmt.Errorf("object %q already exists in the object store", index.AsString())
+	if existing, ok := s.Storage[index]; ok {
+		if policy := duplicatePolicy(index); policy != DuplicateAllow {
+			s.duplicates = append(s.duplicates, Duplicate{
+				Index:  index,
+				Paths:  [2]string{existing.Path, path},
+				Policy: policy,
+			})
+		}
+		return
 	}
 
 	s.Storage[index] = storeObject
-	return nil
+	s.indexes.add(index, &storeObject)
 }
 
 func (s *UnstructuredObjectStore) Get(key ResourceIndex) StoreObject {
@@ -376,6 +123,32 @@ func (s *UnstructuredObjectStore) Exists(key ResourceIndex) bool {
 
 func (s *UnstructuredObjectStore) Close() {
 	s.Storage = make(map[ResourceIndex]StoreObject)
+	s.indexes = newStoreIndexes()
+	s.duplicates = nil
+}
+
+// jsonNumbers converts integers decoded from YAML to int64 like the API server decodes objects,
+// so unstructured.Nested* accessors and DeepCopy work for rendered objects.
+// Whole float64 numbers decoded from JSON are converted too.
+func jsonNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = jsonNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = jsonNumbers(item)
+		}
+	case int:
+		return int64(v)
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v)
+		}
+	}
+
+	return value
 }
 
 func NewSHA256(data []byte) string {
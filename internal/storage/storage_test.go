@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPutNumbers(t *testing.T) {
+	store := NewUnstructuredObjectStore()
+	object := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "test", "namespace": "d8-test"},
+		"spec": map[string]any{
+			"replicas": 2,
+			"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "test", "ports": []any{map[string]any{"containerPort": float64(8080)}}}},
+			}},
+			"ratio": 0.5,
+		},
+	}
+	store.Put("templates/test.yaml", object, nil)
+
+	deployment := store.Get(ResourceIndex{Kind: "Deployment", Name: "test", Namespace: "d8-test"})
+	replicas, found, err := unstructured.NestedInt64(deployment.Unstructured.Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(2), replicas)
+
+	ratio, _, err := unstructured.NestedFloat64(deployment.Unstructured.Object, "spec", "ratio")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, ratio, 0)
+
+	containers, _, err := unstructured.NestedSlice(deployment.Unstructured.Object, "spec", "template", "spec", "containers")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8080), containers[0].(map[string]any)["ports"].([]any)[0].(map[string]any)["containerPort"])
+
+	assert.NotPanics(t, func() { deployment.Unstructured.DeepCopy() })
+}
+
+func TestResourceIndexMatchesAny(t *testing.T) {
+	namespaced := ResourceIndex{Kind: "Secret", Name: "deckhouse-registry", Namespace: "d8-test"}
+	assert.True(t, namespaced.MatchesAny([]string{"*/Secret/deckhouse-registry"}))
+	assert.True(t, namespaced.MatchesAny([]string{"[", "d8-test/Secret/*"}))
+	assert.False(t, namespaced.MatchesAny([]string{"Secret/d8-test/deckhouse-registry", "*/Secret"}))
+
+	clusterScoped := ResourceIndex{Kind: "PriorityClass", Name: "cluster-medium"}
+	assert.True(t, clusterScoped.MatchesAny([]string{"PriorityClass/cluster-*"}))
+	assert.False(t, clusterScoped.MatchesAny(nil))
+}
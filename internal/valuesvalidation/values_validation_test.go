@@ -42,3 +42,126 @@ properties:
 	err = valuesValidator.ValidateJSONValues("nodeManager", []byte(values), false)
 	require.NoError(t, err, message)
 }
+
+// go-openapi/validate is raised by prometheus/prometheus, the test pins behaviour of the schema keywords modules use
+func Test_ValidateValues_schema_keywords(t *testing.T) {
+	const configSchema = `
+type: object
+additionalProperties: false
+required: [logLevel]
+properties:
+  logLevel:
+    type: string
+    enum: [Debug, Info, Error]
+  replicas:
+    type: integer
+    minimum: 1
+    maximum: 5
+  domain:
+    type: string
+    pattern: '^[a-z0-9.-]+$'
+  nodeSelector:
+    type: object
+    additionalProperties:
+      type: string
+  storageClass:
+    oneOf:
+      - type: string
+      - type: boolean
+        enum: [false]
+`
+	const valuesSchema = `
+x-extend:
+  schema: config-values.yaml
+type: object
+properties:
+  internal:
+    type: object
+    default: {}
+    x-required-for-helm: [certificate]
+    properties:
+      certificate:
+        type: string
+`
+
+	globalSchemaStorage, err := validation.NewSchemaStorage([]byte{}, []byte("type: object\n"))
+	require.NoError(t, err, "should load global schema")
+
+	schemaStorage, err := validation.NewSchemaStorage([]byte(configSchema), []byte(valuesSchema))
+	require.NoError(t, err, "should load schemas")
+
+	valuesValidator := &ValuesValidator{
+		GlobalSchemaStorage: globalSchemaStorage,
+		ModuleSchemaStorages: map[string]*validation.SchemaStorage{
+			"testModule": schemaStorage,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  string
+		helm    bool
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			values: `{"global": {}, "testModule": {"logLevel": "Info", "replicas": 2, "domain": "example.com", "nodeSelector": {"a": "b"}, "storageClass": false, "internal": {}}}`,
+		},
+		{
+			name:    "enum",
+			values:  `{"global": {}, "testModule": {"logLevel": "Trace"}}`,
+			wantErr: []string{"testModule.logLevel in body should be one of [Debug Info Error]"},
+		},
+		{
+			name:    "required",
+			values:  `{"global": {}, "testModule": {"replicas": 2}}`,
+			wantErr: []string{"testModule.logLevel in body is required"},
+		},
+		{
+			name:    "type and range",
+			values:  `{"global": {}, "testModule": {"logLevel": "Info", "replicas": 10, "nodeSelector": {"a": 1}}}`,
+			wantErr: []string{"testModule.replicas in body should be less than or equal to 5", `testModule.nodeSelector.a in body must be of type string: "number"`},
+		},
+		{
+			name:    "pattern",
+			values:  `{"global": {}, "testModule": {"logLevel": "Info", "domain": "Example.com"}}`,
+			wantErr: []string{"testModule.domain in body should match '^[a-z0-9.-]+$'"},
+		},
+		{
+			name:    "additional properties",
+			values:  `{"global": {}, "testModule": {"logLevel": "Info", "unknown": true}}`,
+			wantErr: []string{"testModule.unknown in body is a forbidden property"},
+		},
+		{
+			name:    "oneOf",
+			values:  `{"global": {}, "testModule": {"logLevel": "Info", "storageClass": true}}`,
+			wantErr: []string{`"testModule.storageClass" must validate one and only one schema (oneOf). Found none valid`},
+		},
+		{
+			name:    "required for helm",
+			values:  `{"global": {}, "testModule": {"logLevel": "Info", "internal": {}}}`,
+			helm:    true,
+			wantErr: []string{"testModule.internal.certificate in body is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.helm {
+				err = valuesValidator.ValidateHelmValues("test-module", tt.values)
+			} else {
+				err = valuesValidator.ValidateJSONValues("test-module", []byte(tt.values), false)
+			}
+
+			if len(tt.wantErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package config
 
 import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/pod-security-admission/api"
+
 	"github.com/deckhouse/dmt/pkg/errors"
 )
 
@@ -8,8 +16,22 @@ import (
 type Config struct {
 	cfgDir string // The directory containing the config file.
 
-	LintersSettings LintersSettings `mapstructure:"linters-settings"`
-	WarningsOnly    []string        `mapstructure:"warnings-only"`
+	LintersSettings    LintersSettings     `mapstructure:"linters-settings"`
+	WarningsOnly       []string            `mapstructure:"warnings-only"`
+	KubernetesVersions []KubernetesVersion `mapstructure:"kubernetes-versions"`
+	// LibraryChartDirs contains library charts (e.g. helm_lib) or directories with them,
+	// relative paths are resolved against the config file directory
+	LibraryChartDirs []string `mapstructure:"library-chart-dirs"`
+	// CRDDirs contains third-party CustomResourceDefinitions rendered custom resources are validated against
+	// in addition to CustomResourceDefinitions of linted modules, relative paths are resolved against the config file directory
+	CRDDirs []string `mapstructure:"crd-dirs"`
+}
+
+// KubernetesVersion describes a target cluster version modules are rendered for.
+type KubernetesVersion struct {
+	Version string `mapstructure:"version"`
+	// APIVersions contains extra API versions (e.g. "monitoring.coreos.com/v1") served by the cluster
+	APIVersions []string `mapstructure:"api-versions"`
 }
 
 func NewDefault(dirs []string) (*Config, error) {
@@ -21,5 +43,188 @@ func NewDefault(dirs []string) (*Config, error) {
 
 	errors.WarningsOnly = cfg.WarningsOnly
 
+	if err := cfg.setSupportedKubernetesVersions(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateDuplicateObjects(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateExternalSelectors(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateMonitoring(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validatePodSecurityLevel(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateReferences(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateContainerResources(); err != nil {
+		return nil, err
+	}
+
+	cfg.setLibraryValues()
+	cfg.setDaemonSetTolerations()
+
+	cfg.resolvePaths(cfg.LibraryChartDirs)
+	cfg.resolvePaths(cfg.CRDDirs)
+
 	return cfg, nil
 }
+
+// resolvePaths makes relative paths relative to the config file directory
+func (cfg *Config) resolvePaths(paths []string) {
+	if cfg.cfgDir == "" {
+		return
+	}
+
+	for i, p := range paths {
+		if !filepath.IsAbs(p) {
+			paths[i] = filepath.Join(cfg.cfgDir, p)
+		}
+	}
+}
+
+// setSupportedKubernetesVersions defaults the supported Kubernetes versions range to the target versions bounds.
+func (cfg *Config) setSupportedKubernetesVersions() error {
+	var minVersion, maxVersion *version.Version
+
+	for _, kv := range cfg.KubernetesVersions {
+		v, err := version.ParseGeneric(kv.Version)
+		if err != nil {
+			return fmt.Errorf("parse kubernetes version %q: %w", kv.Version, err)
+		}
+
+		if minVersion == nil || v.LessThan(minVersion) {
+			minVersion = v
+		}
+		if maxVersion == nil || maxVersion.LessThan(v) {
+			maxVersion = v
+		}
+	}
+
+	supported := &cfg.LintersSettings.K8SResources.SupportedKubernetesVersions
+	if supported.Min == "" && minVersion != nil {
+		supported.Min = minVersion.String()
+	}
+	if supported.Max == "" && maxVersion != nil {
+		supported.Max = maxVersion.String()
+	}
+
+	return supported.parse()
+}
+
+// parse parses bounds of the range and checks that the lower bound is not greater than the upper one
+func (r *KubernetesVersionsRange) parse() error {
+	var err error
+	if r.Min != "" {
+		if r.minVersion, err = version.ParseGeneric(r.Min); err != nil {
+			return fmt.Errorf("supported kubernetes versions min %q: %w", r.Min, err)
+		}
+	}
+	if r.Max != "" {
+		if r.maxVersion, err = version.ParseGeneric(r.Max); err != nil {
+			return fmt.Errorf("supported kubernetes versions max %q: %w", r.Max, err)
+		}
+	}
+
+	if r.minVersion != nil && r.maxVersion != nil && r.maxVersion.LessThan(r.minVersion) {
+		return fmt.Errorf("supported kubernetes versions range is invalid: min %s is greater than max %s", r.Min, r.Max)
+	}
+
+	return nil
+}
+
+// setLibraryValues defaults values read by library chart helpers to the helm_lib ones
+func (cfg *Config) setLibraryValues() {
+	if cfg.LintersSettings.Values.LibraryValues == nil {
+		cfg.LintersSettings.Values.LibraryValues = DefaultLibraryValues
+	}
+}
+
+// setDaemonSetTolerations defaults taints DaemonSets running on every node must tolerate to control plane ones
+func (cfg *Config) setDaemonSetTolerations() {
+	if cfg.LintersSettings.HighAvailability.DaemonSetTolerations == nil {
+		cfg.LintersSettings.HighAvailability.DaemonSetTolerations = DefaultDaemonSetTolerations
+	}
+}
+
+func (cfg *Config) validateDuplicateObjects() error {
+	for _, p := range cfg.LintersSettings.K8SResources.DuplicateObjects {
+		switch p.Policy {
+		case "error", "warn", "allow":
+		default:
+			return fmt.Errorf("duplicate objects policy %q is invalid, must be one of: error, warn, allow", p.Policy)
+		}
+
+		for _, pattern := range []string{p.Kind, p.Namespace, p.Name} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("duplicate objects pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateExternalSelectors() error {
+	for _, pattern := range cfg.LintersSettings.K8SResources.ExternalSelectors {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("external selectors pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateMonitoring() error {
+	minRateRange := cfg.LintersSettings.Monitoring.MinRateRange
+	if minRateRange == "" {
+		return nil
+	}
+
+	if _, err := model.ParseDuration(minRateRange); err != nil {
+		return fmt.Errorf("monitoring min rate range %q: %w", minRateRange, err)
+	}
+
+	return nil
+}
+
+func (cfg *Config) validatePodSecurityLevel() error {
+	level := cfg.LintersSettings.K8SResources.PodSecurityLevel
+	if level == "" {
+		return nil
+	}
+
+	if _, err := api.ParseLevel(level); err != nil {
+		return fmt.Errorf("pod security level %q is invalid, must be one of: privileged, baseline, restricted", level)
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateReferences() error {
+	for _, pattern := range cfg.LintersSettings.References.ExternalObjects {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("references external object pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateContainerResources() error {
+	if ratio := cfg.LintersSettings.Container.Resources.MaxMemoryLimitRatio; ratio != 0 && ratio < 1 {
+		return fmt.Errorf("container max memory limit ratio %v is invalid, must not be less than 1", ratio)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportedKubernetesVersions(t *testing.T) {
+	cfg := &Config{KubernetesVersions: []KubernetesVersion{{Version: "1.29"}, {Version: "1.27"}}}
+	require.NoError(t, cfg.setSupportedKubernetesVersions())
+	supported := cfg.LintersSettings.K8SResources.SupportedKubernetesVersions
+	assert.Equal(t, "1.27", supported.MinVersion().String())
+	assert.Equal(t, "1.29", supported.MaxVersion().String())
+
+	cfg = &Config{}
+	cfg.LintersSettings.K8SResources.SupportedKubernetesVersions = KubernetesVersionsRange{Min: "1.30", Max: "1.26"}
+	assert.ErrorContains(t, cfg.setSupportedKubernetesVersions(), "min 1.30 is greater than max 1.26")
+
+	cfg.LintersSettings.K8SResources.SupportedKubernetesVersions = KubernetesVersionsRange{Min: "v1.x"}
+	assert.ErrorContains(t, cfg.setSupportedKubernetesVersions(), `supported kubernetes versions min "v1.x"`)
+}
+
+func TestLibraryValues(t *testing.T) {
+	cfg := &Config{}
+	cfg.setLibraryValues()
+	assert.Equal(t, DefaultLibraryValues, cfg.LintersSettings.Values.LibraryValues)
+
+	cfg.LintersSettings.Values.LibraryValues = []string{}
+	cfg.setLibraryValues()
+	assert.Empty(t, cfg.LintersSettings.Values.LibraryValues)
+}
+
+func TestDaemonSetTolerations(t *testing.T) {
+	cfg := &Config{}
+	cfg.setDaemonSetTolerations()
+	assert.Equal(t, DefaultDaemonSetTolerations, cfg.LintersSettings.HighAvailability.DaemonSetTolerations)
+}
+
+func TestContainerResources(t *testing.T) {
+	cfg := &Config{}
+	require.NoError(t, cfg.validateContainerResources())
+
+	cfg.LintersSettings.Container.Resources.MaxMemoryLimitRatio = 1.5
+	require.NoError(t, cfg.validateContainerResources())
+
+	cfg.LintersSettings.Container.Resources.MaxMemoryLimitRatio = 0.5
+	assert.ErrorContains(t, cfg.validateContainerResources(), "container max memory limit ratio 0.5 is invalid")
+}
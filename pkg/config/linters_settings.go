@@ -1,16 +1,24 @@
 package config
 
+import "k8s.io/apimachinery/pkg/util/version"
+
 type LintersSettings struct {
-	OpenAPI      OpenAPISettings      `mapstructure:"openapi"`
-	NoCyrillic   NoCyrillicSettings   `mapstructure:"nocyrillic"`
-	License      LicenseSettings      `mapstructure:"license"`
-	Probes       ProbesSettings       `mapstructure:"probes"`
-	Container    ContainerSettings    `mapstructure:"container"`
-	K8SResources K8SResourcesSettings `mapstructure:"k8s_resources"`
-	Helm         HelmSettings         `mapstructure:"helm"`
-	Rbac         RbacSettings         `mapstructure:"rbac"`
-	Resources    ResourcesSettings    `mapstructure:"resources"`
-	Monitoring   MonitoringSettings   `mapstructure:"monitoring"`
+	OpenAPI          OpenAPISettings          `mapstructure:"openapi"`
+	NoCyrillic       NoCyrillicSettings       `mapstructure:"nocyrillic"`
+	License          LicenseSettings          `mapstructure:"license"`
+	Probes           ProbesSettings           `mapstructure:"probes"`
+	Container        ContainerSettings        `mapstructure:"container"`
+	K8SResources     K8SResourcesSettings     `mapstructure:"k8s_resources"`
+	Helm             HelmSettings             `mapstructure:"helm"`
+	Rbac             RbacSettings             `mapstructure:"rbac"`
+	Resources        ResourcesSettings        `mapstructure:"resources"`
+	Monitoring       MonitoringSettings       `mapstructure:"monitoring"`
+	ModuleYaml       ModuleYamlSettings       `mapstructure:"module_yaml"`
+	Repository       RepositorySettings       `mapstructure:"repository"`
+	Values           ValuesSettings           `mapstructure:"values"`
+	Dashboards       DashboardsSettings       `mapstructure:"dashboards"`
+	HighAvailability HighAvailabilitySettings `mapstructure:"high_availability"`
+	References       ReferencesSettings       `mapstructure:"references"`
 }
 
 type OpenAPISettings struct {
@@ -39,6 +47,16 @@ type ProbesSettings struct {
 
 type ContainerSettings struct {
 	SkipContainers []string `mapstructure:"skip-containers"`
+
+	Resources ContainerResourcesSettings `mapstructure:"resources"`
+}
+
+type ContainerResourcesSettings struct {
+	// MaxMemoryLimitRatio is the highest ratio of the memory limit to the memory request, it is not checked if it is not set
+	MaxMemoryLimitRatio float64 `mapstructure:"max-memory-limit-ratio"`
+	// NoCPULimits contains `name:container` of system components which must not have CPU limits,
+	// the container name may contain `*` as in SkipContainers
+	NoCPULimits []string `mapstructure:"no-cpu-limits"`
 }
 
 type K8SResourcesSettings struct {
@@ -46,12 +64,106 @@ type K8SResourcesSettings struct {
 	SkipContainerChecks     []string `mapstructure:"skip-container-checks"`
 	SkipVPAChecks           []string `mapstructure:"skip-vpa-checks"`
 	SkipPDBChecks           []string `mapstructure:"skip-pdb-checks"`
+	SkipPodSecurityChecks   []string `mapstructure:"skip-pod-security-checks"`
+
+	// PodSecurityLevel is the Pod Security Standards level (privileged, baseline or restricted) enforced in namespaces
+	// without the `pod-security.kubernetes.io/enforce` label, baseline by default
+	PodSecurityLevel string `mapstructure:"pod-security-level"`
+
+	// SupportedKubernetesVersions is the range of Kubernetes versions objects are checked for deprecated APIs against
+	SupportedKubernetesVersions KubernetesVersionsRange `mapstructure:"supported-kubernetes-versions"`
+
+	// DuplicateObjects contains policies for objects rendered more than once by a module, the first matching policy wins
+	DuplicateObjects []DuplicateObjectPolicy `mapstructure:"duplicate-objects"`
+
+	// ExternalSelectors contains `namespace/Kind/name` (`Kind/name` for cluster scoped objects) glob patterns of
+	// Services, ServiceMonitors and PodMonitors selecting objects of other modules
+	ExternalSelectors []string `mapstructure:"external-selectors"`
+}
+
+// DuplicateObjectPolicy matches objects by kind, namespace and name glob patterns, an empty pattern matches anything
+type DuplicateObjectPolicy struct {
+	Kind      string `mapstructure:"kind"`
+	Namespace string `mapstructure:"namespace"`
+	Name      string `mapstructure:"name"`
+	// Policy is one of "error", "warn" or "allow"
+	Policy string `mapstructure:"policy"`
+}
+
+type KubernetesVersionsRange struct {
+	Min string `mapstructure:"min"`
+	Max string `mapstructure:"max"`
+
+	// minVersion and maxVersion are parsed from Min and Max when the config is loaded, nil if they are not set
+	minVersion, maxVersion *version.Version
+}
+
+// MinVersion returns the parsed lower bound of the range, nil if it is not set
+func (r *KubernetesVersionsRange) MinVersion() *version.Version {
+	return r.minVersion
+}
+
+// MaxVersion returns the parsed upper bound of the range, nil if it is not set
+func (r *KubernetesVersionsRange) MaxVersion() *version.Version {
+	return r.maxVersion
 }
 
 type ResourcesSettings struct{}
 
 type MonitoringSettings struct {
 	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+	// MinRateRange is the shortest range of rate(), irate() and increase() in rule expressions, 1m by default
+	MinRateRange string `mapstructure:"min-rate-range"`
+}
+
+type DashboardsSettings struct {
+	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+}
+
+type HighAvailabilitySettings struct {
+	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+	// SkipObjects contains `namespace/Kind/name` patterns of objects not checked
+	SkipObjects []string `mapstructure:"skip-objects"`
+	// DaemonSetTolerations contains taint keys DaemonSets running on every node must tolerate.
+	// DefaultDaemonSetTolerations are used if not set.
+	DaemonSetTolerations []string `mapstructure:"daemonset-tolerations"`
+}
+
+// DefaultDaemonSetTolerations are taints of control plane nodes
+var DefaultDaemonSetTolerations = []string{"node-role.kubernetes.io/control-plane"}
+
+type ReferencesSettings struct {
+	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+	// ExternalObjects contains `namespace/Kind/name` (`Kind/name` for cluster scoped objects) glob patterns
+	// of objects created outside of the module, e.g. by other modules
+	ExternalObjects []string `mapstructure:"external-objects"`
+}
+
+type ModuleYamlSettings struct {
+	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+}
+
+type RepositorySettings struct {
+	// SkipObjects contains objects rendered by several modules on purpose, e.g. "ClusterRole/d8:common" or "d8-system/Secret/registry"
+	SkipObjects []string `mapstructure:"skip-objects"`
+}
+
+type ValuesSettings struct {
+	SkipModuleChecks []string `mapstructure:"skip-module-checks"`
+	// SkipUnusedValues contains module values read by hooks only, e.g. "internal.certificate", by module names
+	SkipUnusedValues map[string][]string `mapstructure:"skip-unused-values"`
+	// LibraryValues contains module values read by library chart helpers with computed keys, they are never unused.
+	// DefaultLibraryValues are used if not set.
+	LibraryValues []string `mapstructure:"library-values"`
+}
+
+// DefaultLibraryValues are module values read by helm_lib helpers with computed keys
+var DefaultLibraryValues = []string{
+	"highAvailability",
+	"https",
+	"nodeSelector",
+	"tolerations",
+	"internal.customCertificateData",
 }
 
 type RbacSettings struct {
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/kyokomi/emoji"
+	"k8s.io/apimachinery/pkg/util/version"
 )
 
 type LintRuleError struct {
@@ -17,6 +19,8 @@ type LintRuleError struct {
 	ObjectID string
 	Value    any
 	Module   string
+	// KubeVersions contains target Kubernetes versions the error was found for
+	KubeVersions []string
 }
 
 func (l *LintRuleError) EqualsTo(candidate *LintRuleError) bool {
@@ -37,19 +41,40 @@ type LintRuleErrorsList struct {
 	data []*LintRuleError
 }
 
+func (l *LintRuleError) addKubeVersions(versions ...string) {
+	for _, version := range versions {
+		if !slices.Contains(l.KubeVersions, version) {
+			l.KubeVersions = append(l.KubeVersions, version)
+		}
+	}
+}
+
 // Add adds new error to the list if it doesn't exist yet.
 // It first checks if error is empty (i.e. all its fields are empty strings)
 // and then checks if error with the same ID, ObjectId and Text already exists in the list.
+// Kubernetes versions of duplicate errors are merged into the existing one.
 func (l *LintRuleErrorsList) Add(e *LintRuleError) {
 	if e == nil {
 		return
 	}
-	if slices.ContainsFunc(l.data, e.EqualsTo) {
+	if i := slices.IndexFunc(l.data, e.EqualsTo); i >= 0 {
+		l.data[i].addKubeVersions(e.KubeVersions...)
 		return
 	}
 	l.data = append(l.data, e)
 }
 
+// SetKubeVersion marks all errors in the list as found for the target Kubernetes version.
+// Empty version is ignored.
+func (l *LintRuleErrorsList) SetKubeVersion(version string) {
+	if version == "" {
+		return
+	}
+	for _, el := range l.data {
+		el.addKubeVersions(version)
+	}
+}
+
 // Merge merges another LintRuleErrorsList into current one, removing all duplicate errors.
 func (l *LintRuleErrorsList) Merge(e LintRuleErrorsList) {
 	for _, el := range e.data {
@@ -57,6 +82,25 @@ func (l *LintRuleErrorsList) Merge(e LintRuleErrorsList) {
 	}
 }
 
+// MarshalJSON encodes the list. Values are encoded as they are printed,
+// so the decoded list is printed the same way as the original one.
+func (l LintRuleErrorsList) MarshalJSON() ([]byte, error) {
+	data := make([]LintRuleError, 0, len(l.data))
+	for _, el := range l.data {
+		e := *el
+		if e.Value != nil {
+			e.Value = fmt.Sprintf("%v", e.Value)
+		}
+		data = append(data, e)
+	}
+
+	return json.Marshal(data)
+}
+
+func (l *LintRuleErrorsList) UnmarshalJSON(content []byte) error {
+	return json.Unmarshal(content, &l.data)
+}
+
 // ConvertToError converts LintRuleErrorsList to a single error.
 // It returns an error that contains all errors from the list with a nice formatting.
 // If the list is empty, it returns nil.
@@ -68,6 +112,8 @@ func (l *LintRuleErrorsList) ConvertToError() error {
 		return cmp.Or(
 			cmp.Compare(a.Module, b.Module),
 			cmp.Compare(a.ObjectID, b.ObjectID),
+			cmp.Compare(a.ID, b.ID),
+			cmp.Compare(a.Text, b.Text),
 		)
 	})
 	builder := strings.Builder{}
@@ -81,6 +127,11 @@ func (l *LintRuleErrorsList) ConvertToError() error {
 			err.Module,
 		))
 
+		if len(err.KubeVersions) > 0 {
+			slices.SortFunc(err.KubeVersions, compareVersions)
+			builder.WriteString(fmt.Sprintf("\tKubernetes\t- %s\n", strings.Join(err.KubeVersions, ", ")))
+		}
+
 		if err.Value != nil {
 			value := fmt.Sprintf("%v", err.Value)
 			builder.WriteString(fmt.Sprintf("\tValue\t- %s\n", value))
@@ -91,6 +142,22 @@ func (l *LintRuleErrorsList) ConvertToError() error {
 	return errors.New(builder.String())
 }
 
+func compareVersions(a, b string) int {
+	va, errA := version.ParseGeneric(a)
+	vb, errB := version.ParseGeneric(b)
+	if errA != nil || errB != nil {
+		return cmp.Compare(a, b)
+	}
+	switch {
+	case va.LessThan(vb):
+		return -1
+	case vb.LessThan(va):
+		return 1
+	default:
+		return 0
+	}
+}
+
 var WarningsOnly []string
 
 func (l *LintRuleErrorsList) Critical() bool {
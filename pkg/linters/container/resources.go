@@ -0,0 +1,107 @@
+package container
+
+import (
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/vpa"
+)
+
+// containersResources checks requests and limits of containers against the resources policy:
+// CPU and memory requests are required unless a VPA manages the container, memory limits are required
+// and must not exceed the memory request more than MaxMemoryLimitRatio times, system components listed
+// in NoCPULimits must not have CPU limits. Static requests of containers managed by a VPA must be within
+// the VPA minAllowed and maxAllowed range.
+func containersResources(m *module.Module, object storage.StoreObject, template *v1.PodTemplateSpec) (result errors.LintRuleErrorsList) {
+	containers := slices.Concat(template.Spec.InitContainers, template.Spec.Containers)
+	for i := range containers {
+		if shouldSkipModuleContainer(object.Unstructured.GetName(), containers[i].Name) {
+			continue
+		}
+
+		var policy *vpa.ContainerResourcePolicy
+		// VPA does not manage init containers
+		if i >= len(template.Spec.InitContainers) {
+			policy, _ = vpa.ManagedContainerPolicy(m, object, containers[i].Name)
+		}
+
+		result.Merge(containerResources(object, &containers[i], policy))
+	}
+
+	return result
+}
+
+// containerResources checks resources of the container, the policy is nil if VPA does not manage the container
+func containerResources(object storage.StoreObject, c *v1.Container, policy *vpa.ContainerResourcePolicy) (result errors.LintRuleErrorsList) {
+	add := func(value any, template string, args ...any) {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			object.Identity()+"; container = "+c.Name,
+			c.Name,
+			value,
+			template, args...,
+		))
+	}
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		request, ok := c.Resources.Requests[name]
+		switch {
+		case (policy == nil || !policy.Controls(name)) && (!ok || request.IsZero()):
+			add(nil, "%s request for container is not defined in Resources.Requests", resourceTitle(name))
+		case policy != nil && ok && policy.Controls(name):
+			if err := vpaRangeError(policy, name, request); err != nil {
+				add(request.String(), "%s", err)
+			}
+		}
+	}
+
+	memoryLimit, hasMemoryLimit := c.Resources.Limits[v1.ResourceMemory]
+	if !hasMemoryLimit || memoryLimit.IsZero() {
+		add(nil, "Memory limit for container is not defined in Resources.Limits")
+	} else if memoryRequest, ok := c.Resources.Requests[v1.ResourceMemory]; ok && !memoryRequest.IsZero() {
+		ratio := float64(memoryLimit.Value()) / float64(memoryRequest.Value())
+		if Cfg.Resources.MaxMemoryLimitRatio != 0 && ratio > Cfg.Resources.MaxMemoryLimitRatio {
+			add(ratio, "Memory limit %s is more than %v times the memory request %s",
+				memoryLimit.String(), Cfg.Resources.MaxMemoryLimitRatio, memoryRequest.String())
+		}
+	}
+
+	if hasMemoryLimit && policy != nil && policy.Controls(v1.ResourceMemory) && !policy.ControlsLimits() {
+		if maxAllowed, ok := policy.MaxAllowed[v1.ResourceMemory]; ok && memoryLimit.Cmp(maxAllowed) < 0 {
+			add(memoryLimit.String(), "Memory limit %s is less than VPA maxAllowed.memory %s, VPA with RequestsOnly controlled values cannot raise the request above the limit",
+				memoryLimit.String(), maxAllowed.String())
+		}
+	}
+
+	if _, ok := c.Resources.Limits[v1.ResourceCPU]; ok && matchContainer(Cfg.Resources.NoCPULimits, object.Unstructured.GetName(), c.Name) {
+		add(nil, "CPU limit must not be set for the system component, it causes CPU throttling")
+	}
+
+	return result
+}
+
+// vpaRangeError reports static requests VPA would change right after the pod is created
+func vpaRangeError(policy *vpa.ContainerResourcePolicy, name v1.ResourceName, request resource.Quantity) error {
+	if minAllowed, ok := policy.MinAllowed[name]; ok && request.Cmp(minAllowed) < 0 {
+		return fmt.Errorf("%s request %s is less than VPA minAllowed.%s %s", resourceTitle(name), request.String(), name, minAllowed.String())
+	}
+	if maxAllowed, ok := policy.MaxAllowed[name]; ok && request.Cmp(maxAllowed) > 0 {
+		return fmt.Errorf("%s request %s is more than VPA maxAllowed.%s %s", resourceTitle(name), request.String(), name, maxAllowed.String())
+	}
+
+	return nil
+}
+
+func resourceTitle(name v1.ResourceName) string {
+	if name == v1.ResourceCPU {
+		return "CPU"
+	}
+
+	return "Memory"
+}
@@ -0,0 +1,70 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/vpa"
+)
+
+func TestContainerResources(t *testing.T) {
+	Cfg = &config.ContainerSettings{
+		Resources: config.ContainerResourcesSettings{
+			MaxMemoryLimitRatio: 2,
+			NoCPULimits:         []string{"agent:*"},
+		},
+	}
+
+	object := storage.StoreObject{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata":   map[string]any{"name": "agent", "namespace": "d8-test"},
+	}}}
+	c := &v1.Container{
+		Name: "agent",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("50Mi")},
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("200Mi"),
+			},
+		},
+	}
+
+	result := containerResources(object, c, nil)
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "CPU request for container is not defined in Resources.Requests")
+	assert.NotContains(t, text, "Memory request for container")
+	assert.Contains(t, text, "Memory limit 200Mi is more than 2 times the memory request 50Mi")
+	assert.Contains(t, text, "CPU limit must not be set for the system component")
+
+	requestsOnly := vpa.ContainerControlledValuesRequestsOnly
+	policy := &vpa.ContainerResourcePolicy{
+		ContainerName:    "agent",
+		MinAllowed:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("100Mi")},
+		MaxAllowed:       v1.ResourceList{v1.ResourceMemory: resource.MustParse("500Mi")},
+		ControlledValues: &requestsOnly,
+	}
+
+	result = containerResources(object, c, policy)
+	text = result.ConvertToError().Error()
+	assert.NotContains(t, text, "CPU request for container is not defined")
+	assert.Contains(t, text, "Memory request 50Mi is less than VPA minAllowed.memory 100Mi")
+	assert.Contains(t, text, "Memory limit 200Mi is less than VPA maxAllowed.memory 500Mi")
+
+	// requests of resources VPA does not control must be set statically
+	memoryOnly := []v1.ResourceName{v1.ResourceMemory}
+	policy.ControlledResources = &memoryOnly
+
+	result = containerResources(object, c, policy)
+	text = result.ConvertToError().Error()
+	assert.Contains(t, text, "CPU request for container is not defined in Resources.Requests")
+	assert.NotContains(t, text, "Memory request for container")
+	assert.Contains(t, text, "Memory request 50Mi is less than VPA minAllowed.memory 100Mi")
+}
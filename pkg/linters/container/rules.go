@@ -2,27 +2,25 @@ package container
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/internal/storage"
 	"github.com/deckhouse/dmt/pkg/errors"
 )
 
 const defaultRegistry = "registry.example.com/deckhouse"
 
-func applyContainerRules(object storage.StoreObject) (result errors.LintRuleErrorsList) {
-	containers, err := object.GetContainers()
-	if err != nil {
+func applyContainerRules(m *module.Module, object storage.StoreObject) (result errors.LintRuleErrorsList) {
+	template, err := object.PodTemplate()
+	if err != nil || template == nil {
 		return
 	}
-	initContainers, err := object.GetInitContainers()
-	if err != nil {
-		return
-	}
-	containers = append(initContainers, containers...)
+	containers := slices.Concat(template.Spec.InitContainers, template.Spec.Containers)
 	if len(containers) == 0 {
 		return
 	}
@@ -35,6 +33,7 @@ func applyContainerRules(object storage.StoreObject) (result errors.LintRuleErro
 	result.Add(containersImagePullPolicy(object, containers))
 
 	result.Add(containerStorageEphemeral(object, containers))
+	result.Merge(containersResources(m, object, template))
 	result.Add(containerSecurityContext(object, containers))
 	result.Add(containerPorts(object, containers))
 
@@ -106,7 +105,12 @@ func containerEnvVariablesDuplicates(object storage.StoreObject, containers []v1
 }
 
 func shouldSkipModuleContainer(md, container string) bool {
-	for _, line := range Cfg.SkipContainers {
+	return matchContainer(Cfg.SkipContainers, md, container)
+}
+
+// matchContainer reports whether `name:container` patterns match the container, the container name may contain `*`
+func matchContainer(patterns []string, md, container string) bool {
+	for _, line := range patterns {
 		els := strings.Split(line, ":")
 		if len(els) != 2 {
 			continue
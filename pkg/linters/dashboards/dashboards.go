@@ -0,0 +1,47 @@
+package dashboards
+
+import (
+	"slices"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "dashboards"
+)
+
+// Dashboards linter
+type Dashboards struct {
+	name, desc string
+	cfg        *config.DashboardsSettings
+}
+
+func New(cfg *config.DashboardsSettings) *Dashboards {
+	return &Dashboards{
+		name: "dashboards",
+		desc: "Lint Grafana dashboards of the monitoring folder",
+		cfg:  cfg,
+	}
+}
+
+func (o *Dashboards) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+	if m == nil {
+		return result, err
+	}
+
+	if slices.Contains(o.cfg.SkipModuleChecks, m.GetName()) {
+		return result, err
+	}
+
+	return dashboardsRules(m.GetName(), m.GetPath()), nil
+}
+
+func (o *Dashboards) Name() string {
+	return o.name
+}
+
+func (o *Dashboards) Desc() string {
+	return o.desc
+}
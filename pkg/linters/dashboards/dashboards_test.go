@@ -0,0 +1,68 @@
+package dashboards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mainDashboard = `{
+  "id": 12,
+  "uid": "main",
+  "templating": {"list": [{"name": "ds_prometheus", "type": "datasource", "query": "prometheus"}]},
+  "panels": [
+    {
+      "id": 1,
+      "title": "Requests",
+      "type": "timeseries",
+      "datasource": {"type": "prometheus", "uid": "$ds_prometheus"},
+      "targets": [
+        {"refId": "A", "expr": "sum by ($group) (rate(requests_total{namespace=~\"$namespace\"}[$__rate_interval]))"},
+        {"refId": "B", "expr": "topk($top, rate(requests_total[5m:$__interval]))"}
+      ]
+    },
+    {
+      "id": 2,
+      "title": "Row",
+      "type": "row",
+      "collapsed": true,
+      "panels": [
+        {
+          "id": 3,
+          "title": "Errors",
+          "type": "graph",
+          "datasource": {"type": "prometheus", "uid": "P1809F7CD0C75ACF3"},
+          "targets": [{"refId": "A", "expr": "sum(rate(errors_total[5m]) by (pod)"}]
+        }
+      ]
+    }
+  ]
+}`
+
+const otherDashboard = `{
+  "uid": "main",
+  "rows": [{"panels": [{"id": 1, "title": "Uptime", "type": "singlestat", "datasource": "Prometheus"}]}]
+}`
+
+func TestDashboardsRules(t *testing.T) {
+	modulePath := t.TempDir()
+	dir := filepath.Join(modulePath, DashboardsDir, "main")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(mainDashboard), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(otherDashboard), 0o600))
+
+	result := dashboardsRules("test", modulePath)
+	text := result.ConvertToError().Error()
+
+	assert.Contains(t, text, "Dashboard must not have the `id` field")
+	assert.NotContains(t, text, `Panel "Requests"`)
+	assert.Contains(t, text, `Panel "Errors" has the deprecated type "graph", use "timeseries" instead`)
+	assert.Contains(t, text, `Panel "Errors" uses the hard-coded datasource "P1809F7CD0C75ACF3"`)
+	assert.Contains(t, text, `Panel "Errors" target "A" has an invalid PromQL expression`)
+	assert.Contains(t, text, `Panel "Uptime" has the deprecated type "singlestat", use "stat" instead`)
+	assert.Contains(t, text, `Panel "Uptime" uses the hard-coded datasource "Prometheus"`)
+	assert.Contains(t, text, `Dashboard uid "main" is already used by monitoring/grafana-dashboards/main/a.json`)
+}
@@ -0,0 +1,49 @@
+package dashboards
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+var (
+	// variableRe matches Grafana variables: $var, ${var}, ${var:format} and the deprecated [[var]]
+	variableRe = regexp.MustCompile(`\$\{[^}]*\}|\$[a-zA-Z_][a-zA-Z0-9_]*|\[\[[^\]]*\]\]`)
+	// durationContextRe matches the expression before a variable used as a range, a subquery step or an offset
+	durationContextRe = regexp.MustCompile(`(\[|:|offset\s+)$`)
+)
+
+// variablePlaceholders replace variables used out of durations: as label names, metric names or numbers
+var variablePlaceholders = []string{"grafana_variable", "1"}
+
+// parseExpression parses the PromQL expression of a panel target with Grafana variables replaced.
+// Variables used as durations are replaced with a duration, other variables are replaced with a name
+// and then with a number, the expression is valid if any of them parses.
+func parseExpression(expr string) error {
+	var err error
+	for _, placeholder := range variablePlaceholders {
+		if _, err = parser.ParseExpr(replaceVariables(expr, placeholder)); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func replaceVariables(expr, placeholder string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range variableRe.FindAllStringIndex(expr, -1) {
+		b.WriteString(expr[last:loc[0]])
+		if durationContextRe.MatchString(expr[:loc[0]]) {
+			b.WriteString("5m")
+		} else {
+			b.WriteString(placeholder)
+		}
+		last = loc[1]
+	}
+	b.WriteString(expr[last:])
+
+	return b.String()
+}
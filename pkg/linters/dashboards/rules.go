@@ -0,0 +1,202 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+// DashboardsDir contains Grafana dashboards of the module grouped by folders
+var DashboardsDir = filepath.Join("monitoring", "grafana-dashboards")
+
+// deprecatedPanelTypes contains panel types removed from Grafana or replaced by core panels, by their replacements
+var deprecatedPanelTypes = map[string]string{
+	"graph":                    "timeseries",
+	"singlestat":               "stat",
+	"grafana-singlestat-panel": "stat",
+	"table-old":                "table",
+	"grafana-piechart-panel":   "piechart",
+	"grafana-worldmap-panel":   "geomap",
+}
+
+// allowedDatasources are the Prometheus datasource variable and Grafana built-in datasources
+var allowedDatasources = []string{
+	"$ds_prometheus",
+	"${ds_prometheus}",
+	"grafana",
+	"-- Grafana --",
+	"-- Mixed --",
+	"-- Dashboard --",
+}
+
+// dashboardsRules checks every JSON dashboard in the monitoring folder of the module
+func dashboardsRules(moduleName, modulePath string) (result errors.LintRuleErrorsList) {
+	dir := filepath.Join(modulePath, DashboardsDir)
+	if _, err := os.Stat(dir); err != nil {
+		return result
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".json" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			DashboardsDir,
+			moduleName,
+			nil,
+			"Cannot read dashboards: %s", err,
+		))
+		return result
+	}
+
+	// uids contains dashboard paths by their uid
+	uids := make(map[string]string)
+	for _, file := range files {
+		path, _ := filepath.Rel(modulePath, file)
+		d := &dashboard{moduleName: moduleName, path: path}
+
+		content, err := os.ReadFile(file)
+		if err == nil {
+			err = json.Unmarshal(content, &d.content)
+		}
+		if err != nil {
+			d.add(nil, "Cannot parse dashboard: %s", err)
+			result.Merge(d.result)
+			continue
+		}
+
+		d.check()
+
+		if uid, _ := d.content["uid"].(string); uid != "" {
+			if first, ok := uids[uid]; ok {
+				d.add(uid, "Dashboard uid %q is already used by %s", uid, first)
+			} else {
+				uids[uid] = path
+			}
+		}
+
+		result.Merge(d.result)
+	}
+
+	return result
+}
+
+// dashboard collects findings of a parsed dashboard
+type dashboard struct {
+	moduleName string
+	// path is relative to the module
+	path    string
+	content map[string]any
+	result  errors.LintRuleErrorsList
+}
+
+func (d *dashboard) add(value any, template string, args ...any) {
+	d.result.Add(errors.NewLintRuleError(ID, d.path, d.moduleName, value, template, args...))
+}
+
+func (d *dashboard) check() {
+	if id, ok := d.content["id"]; ok && id != nil {
+		d.add(id, "Dashboard must not have the `id` field, Grafana assigns it on import")
+	}
+
+	if templating, ok := d.content["templating"].(map[string]any); ok {
+		for _, variable := range objects(templating["list"]) {
+			name, _ := variable["name"].(string)
+			d.checkDatasource(variable["datasource"], fmt.Sprintf("Variable %q", name))
+		}
+	}
+
+	for _, panel := range objects(d.content["panels"]) {
+		d.checkPanel(panel)
+	}
+	// dashboards of schema versions before 16 keep panels in rows
+	for _, row := range objects(d.content["rows"]) {
+		for _, panel := range objects(row["panels"]) {
+			d.checkPanel(panel)
+		}
+	}
+}
+
+func (d *dashboard) checkPanel(panel map[string]any) {
+	name := panelName(panel)
+
+	panelType, _ := panel["type"].(string)
+	if replacement, ok := deprecatedPanelTypes[panelType]; ok {
+		d.add(name, "%s has the deprecated type %q, use %q instead", name, panelType, replacement)
+	}
+
+	d.checkDatasource(panel["datasource"], name)
+
+	for _, target := range objects(panel["targets"]) {
+		refID, _ := target["refId"].(string)
+		targetName := fmt.Sprintf("%s target %q", name, refID)
+
+		d.checkDatasource(target["datasource"], targetName)
+
+		expr, _ := target["expr"].(string)
+		if expr == "" {
+			continue
+		}
+		if err := parseExpression(expr); err != nil {
+			d.add(expr, "%s has an invalid PromQL expression: %s", targetName, err)
+		}
+	}
+
+	// collapsed rows contain their panels
+	for _, nested := range objects(panel["panels"]) {
+		d.checkPanel(nested)
+	}
+}
+
+// checkDatasource reports datasources set by uid or name instead of the $ds_prometheus variable
+func (d *dashboard) checkDatasource(datasource any, name string) {
+	var ref string
+	switch ds := datasource.(type) {
+	case string:
+		ref = ds
+	case map[string]any:
+		// a datasource without uid is the default datasource of its type
+		ref, _ = ds["uid"].(string)
+	}
+
+	if ref == "" || slices.Contains(allowedDatasources, ref) {
+		return
+	}
+
+	d.add(ref, "%s uses the hard-coded datasource %q, use the `$ds_prometheus` variable instead", name, ref)
+}
+
+func panelName(panel map[string]any) string {
+	if title, _ := panel["title"].(string); title != "" {
+		return fmt.Sprintf("Panel %q", title)
+	}
+
+	return fmt.Sprintf("Panel #%v", panel["id"])
+}
+
+// objects returns JSON objects of the array, other values are skipped
+func objects(v any) []map[string]any {
+	list, _ := v.([]any)
+
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if object, ok := item.(map[string]any); ok {
+			result = append(result, object)
+		}
+	}
+
+	return result
+}
@@ -31,41 +31,50 @@ var Cfg *config.HelmSettings
 
 var toHelmignore = []string{HooksDir, openapiDir, CrdsDir, ImagesDir, "enabled"}
 
-func namespaceModuleRule(name, path string) (string, *errors.LintRuleError) {
-	content, err := os.ReadFile(filepath.Join(path, ".namespace"))
+func namespaceModuleRule(name, path string, def *module.Definition) (string, *errors.LintRuleError) {
+	if def != nil && def.Namespace != "" {
+		return def.Namespace, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(path, module.NamespaceFilename))
 	if err != nil {
 		return "", errors.NewLintRuleError(
 			ID,
 			name,
 			name,
 			nil,
-			`Module does not contain ".namespace" file, module will be ignored`,
+			`Module does not contain ".namespace" file or "namespace" in %q, module will be ignored`, module.ModuleYamlFilename,
 		)
 	}
 	return strings.TrimRight(string(content), " \t\n"), nil
 }
 
-func chartModuleRule(name, path string) (string, *errors.LintRuleError) {
+func chartModuleRule(name, path string, def *module.Definition) (string, *errors.LintRuleError) {
 	lintError := errors.NewLintRuleError(
 		ID,
 		name,
 		name,
 		nil,
-		"Module does not contain valid %q file, module will be ignored", ChartConfigFilename,
+		"Module does not contain valid %q or %q file, module will be ignored", ChartConfigFilename, module.ModuleYamlFilename,
 	)
 
-	// TODO: Chart.yaml could be absent if we have module.yaml
-	yamlFile, err := os.ReadFile(filepath.Join(path, ChartConfigFilename))
-	if err != nil {
-		return "", lintError
-	}
-
 	var chart struct {
 		Name string `yaml:"name"`
 	}
-	err = yaml.Unmarshal(yamlFile, &chart)
-	if err != nil {
-		return "", lintError
+
+	// Chart.yaml could be absent if we have module.yaml
+	if def != nil && def.Name != "" {
+		chart.Name = def.Name
+	} else {
+		yamlFile, err := os.ReadFile(filepath.Join(path, ChartConfigFilename))
+		if err != nil {
+			return "", lintError
+		}
+
+		err = yaml.Unmarshal(yamlFile, &chart)
+		if err != nil {
+			return "", lintError
+		}
 	}
 
 	if !IsExistsOnFilesystem(path, ValuesConfigFilename) && !IsExistsOnFilesystem(path, openapiDir) {
@@ -135,13 +144,13 @@ func ApplyHelmRules(m *module.Module) (result errors.LintRuleErrorsList) {
 	result.Add(helmignoreModuleRule(m.GetName(), m.GetPath()))
 	result.Merge(CheckImageNamesInDockerAndWerfFiles(m.GetName(), m.GetPath()))
 
-	name, lintError := chartModuleRule(m.GetName(), m.GetPath())
+	name, lintError := chartModuleRule(m.GetName(), m.GetPath(), m.GetDefinition())
 	result.Add(lintError)
 	if name == "" {
 		return result
 	}
 
-	namespace, lintError := namespaceModuleRule(m.GetName(), m.GetPath())
+	namespace, lintError := namespaceModuleRule(m.GetName(), m.GetPath(), m.GetDefinition())
 	result.Add(lintError)
 	if namespace == "" {
 		return result
@@ -0,0 +1,66 @@
+package highavailability
+
+import (
+	"slices"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "high-availability"
+)
+
+// HighAvailability linter
+type HighAvailability struct {
+	name, desc string
+	cfg        *config.HighAvailabilitySettings
+}
+
+func New(cfg *config.HighAvailabilitySettings) *HighAvailability {
+	return &HighAvailability{
+		name: "high-availability",
+		desc: "Lint workloads rendered with the high availability mode turned on and off",
+		cfg:  cfg,
+	}
+}
+
+func (o *HighAvailability) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+	if m == nil {
+		return result, err
+	}
+
+	if slices.Contains(o.cfg.SkipModuleChecks, m.GetName()) {
+		return result, err
+	}
+
+	for _, enabled := range []bool{false, true} {
+		store, err := m.GetHAObjectStore(enabled)
+		if err != nil {
+			result.Add(errors.NewLintRuleError(
+				ID,
+				m.GetName(),
+				m.GetName(),
+				enabled,
+				"Cannot render the module: %s", err,
+			))
+			continue
+		}
+		if store == nil {
+			continue
+		}
+
+		result.Merge(haRules(m.GetName(), store, enabled, o.cfg))
+	}
+
+	return result, nil
+}
+
+func (o *HighAvailability) Name() string {
+	return o.name
+}
+
+func (o *HighAvailability) Desc() string {
+	return o.desc
+}
@@ -0,0 +1,184 @@
+package highavailability
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+// haRules checks objects rendered with the high availability mode enabled or disabled:
+// replicated Deployments and StatefulSets must spread pods with pod anti-affinity or topology spread constraints,
+// PodDisruptionBudgets must allow evicting some but not all replicas and DaemonSets without a node selector
+// must tolerate taints of cfg.DaemonSetTolerations. Objects matching cfg.SkipObjects `namespace/Kind/name` patterns are not checked.
+func haRules(moduleName string, store *storage.UnstructuredObjectStore, enabled bool, cfg *config.HighAvailabilitySettings) (result errors.LintRuleErrorsList) {
+	skipped := func(object storage.StoreObject) bool {
+		index := storage.GetResourceIndex(object)
+		return index.MatchesAny(cfg.SkipObjects)
+	}
+	add := func(object storage.StoreObject, template string, args ...any) {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			object.Identity(),
+			moduleName,
+			fmt.Sprintf("highAvailability=%t", enabled),
+			template, args...,
+		))
+	}
+
+	for _, object := range store.ByKind("Deployment", "StatefulSet") {
+		if skipped(object) {
+			continue
+		}
+
+		replicas, err := objectReplicas(object)
+		if err != nil {
+			add(object, "Cannot convert object: %s", err)
+			continue
+		}
+
+		template, err := object.PodTemplate()
+		if err != nil || template == nil {
+			continue
+		}
+		if replicas > 1 && !spreadsPods(&template.Spec) {
+			add(object, "Object with %d replicas has neither pod anti-affinity nor topology spread constraints, replicas may run on the same node", replicas)
+		}
+	}
+
+	for _, object := range store.ByKind("PodDisruptionBudget") {
+		if skipped(object) {
+			continue
+		}
+		pdbRules(store, object, add)
+	}
+
+	for _, object := range store.ByKind("DaemonSet") {
+		if skipped(object) {
+			continue
+		}
+
+		template, err := object.PodTemplate()
+		if err != nil || template == nil || selectsNodes(&template.Spec) {
+			continue
+		}
+		for _, key := range cfg.DaemonSetTolerations {
+			if !tolerates(template.Spec.Tolerations, key) {
+				add(object, "DaemonSet running on every node does not tolerate the %q taint", key)
+			}
+		}
+	}
+
+	return result
+}
+
+// pdbRules checks disruptions the PodDisruptionBudget allows for replicas of selected Deployments and StatefulSets
+func pdbRules(store *storage.UnstructuredObjectStore, object storage.StoreObject, add func(object storage.StoreObject, template string, args ...any)) {
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Unstructured.UnstructuredContent(), pdb); err != nil {
+		add(object, "Cannot convert object: %s", err)
+		return
+	}
+
+	controllers, err := store.SelectedPods(object)
+	if err != nil {
+		add(object, "Cannot get selected pods: %s", err)
+		return
+	}
+
+	for _, controller := range controllers {
+		switch controller.Unstructured.GetKind() {
+		case "Deployment", "StatefulSet":
+		default:
+			continue
+		}
+
+		replicas, err := objectReplicas(controller)
+		if err != nil || replicas == 0 {
+			continue
+		}
+
+		allowed, ok := allowedDisruptions(pdb, replicas)
+		switch {
+		case !ok:
+		case allowed <= 0:
+			add(controller, "PodDisruptionBudget %q allows no disruptions of %d replicas, it blocks node drains", pdb.Name, replicas)
+		case replicas > 1 && allowed >= replicas:
+			add(controller, "PodDisruptionBudget %q allows evicting all %d replicas at once", pdb.Name, replicas)
+		}
+	}
+}
+
+// allowedDisruptions returns how many of replicas the PodDisruptionBudget allows to evict,
+// percentages are rounded up like the disruption controller does
+func allowedDisruptions(pdb *policyv1.PodDisruptionBudget, replicas int) (int, bool) {
+	switch {
+	case pdb.Spec.MinAvailable != nil:
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, replicas, true)
+		if err != nil {
+			return 0, false
+		}
+		return replicas - minAvailable, true
+	case pdb.Spec.MaxUnavailable != nil:
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, replicas, true)
+		if err != nil {
+			return 0, false
+		}
+		return maxUnavailable, true
+	default:
+		return 0, false
+	}
+}
+
+// objectReplicas returns replicas of the Deployment or StatefulSet, 1 if it is not set
+func objectReplicas(object storage.StoreObject) (int, error) {
+	replicas, found, err := unstructured.NestedInt64(object.Unstructured.Object, "spec", "replicas")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 1, nil
+	}
+
+	return int(replicas), nil
+}
+
+func spreadsPods(spec *v1.PodSpec) bool {
+	if len(spec.TopologySpreadConstraints) > 0 {
+		return true
+	}
+	if spec.Affinity == nil || spec.Affinity.PodAntiAffinity == nil {
+		return false
+	}
+
+	antiAffinity := spec.Affinity.PodAntiAffinity
+	return len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0 ||
+		len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0
+}
+
+// selectsNodes reports whether pods run on some nodes only
+func selectsNodes(spec *v1.PodSpec) bool {
+	if len(spec.NodeSelector) > 0 {
+		return true
+	}
+
+	return spec.Affinity != nil && spec.Affinity.NodeAffinity != nil &&
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil
+}
+
+func tolerates(tolerations []v1.Toleration, key string) bool {
+	for _, toleration := range tolerations {
+		if toleration.Key == key || (toleration.Key == "" && toleration.Operator == v1.TolerationOpExists) {
+			return true
+		}
+	}
+
+	return false
+}
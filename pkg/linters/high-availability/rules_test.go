@@ -0,0 +1,108 @@
+package highavailability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deckhouse/dmt/internal/module/moduletest"
+	"github.com/deckhouse/dmt/pkg/config"
+)
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+  namespace: d8-test
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: controller
+  template:
+    metadata:
+      labels:
+        app: controller
+    spec:
+      containers:
+        - name: controller
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: controller
+  namespace: d8-test
+spec:
+  minAvailable: 3
+  selector:
+    matchLabels:
+      app: controller
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: webhook
+  namespace: d8-test
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: webhook
+  template:
+    metadata:
+      labels:
+        app: webhook
+    spec:
+      topologySpreadConstraints:
+        - maxSkew: 1
+          topologyKey: kubernetes.io/hostname
+          whenUnsatisfiable: DoNotSchedule
+      containers:
+        - name: webhook
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: webhook
+  namespace: d8-test
+spec:
+  maxUnavailable: 100%
+  selector:
+    matchLabels:
+      app: webhook
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+  namespace: d8-test
+spec:
+  template:
+    spec:
+      tolerations:
+        - key: node-role.kubernetes.io/master
+      containers:
+        - name: agent
+`
+
+func TestHARules(t *testing.T) {
+	store := moduletest.ObjectStore(t, manifests)
+
+	cfg := &config.HighAvailabilitySettings{DaemonSetTolerations: config.DefaultDaemonSetTolerations}
+	result := haRules("test", store, true, cfg)
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "Object with 3 replicas has neither pod anti-affinity nor topology spread constraints")
+	assert.NotContains(t, text, "Object with 2 replicas")
+	assert.Contains(t, text, `PodDisruptionBudget "controller" allows no disruptions of 3 replicas`)
+	assert.Contains(t, text, `PodDisruptionBudget "webhook" allows evicting all 2 replicas at once`)
+	assert.Contains(t, text, `DaemonSet running on every node does not tolerate the "node-role.kubernetes.io/control-plane" taint`)
+
+	cfg.DaemonSetTolerations = []string{"node-role.kubernetes.io/master"}
+	result = haRules("test", store, true, cfg)
+	assert.NotContains(t, result.ConvertToError().Error(), "DaemonSet running on every node")
+
+	cfg.SkipObjects = []string{"d8-test/*/controller", "d8-test/*/webhook", "d8-test/DaemonSet/agent"}
+	result = haRules("test", store, true, cfg)
+	assert.NoError(t, result.ConvertToError())
+}
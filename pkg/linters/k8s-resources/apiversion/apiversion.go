@@ -0,0 +1,116 @@
+package apiversion
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	// ID is used for APIs removed within the supported Kubernetes versions
+	ID = "api-version"
+	// DeprecatedID is used for APIs deprecated, but still served by all supported Kubernetes versions
+	DeprecatedID = "deprecated-api-version"
+
+	// DefaultKubernetesVersion is used as the supported versions range bound if it is not configured
+	DefaultKubernetesVersion = "1.30"
+)
+
+var (
+	minVersion = version.MustParseGeneric(DefaultKubernetesVersion)
+	maxVersion = version.MustParseGeneric(DefaultKubernetesVersion)
+)
+
+// SetSupportedVersions sets the range of Kubernetes versions modules must be compatible with.
+// A nil bound falls back to the other one or DefaultKubernetesVersion. Bounds are validated when the config is loaded.
+func SetSupportedVersions(minV, maxV *version.Version) {
+	defaultVersion := version.MustParseGeneric(DefaultKubernetesVersion)
+
+	switch {
+	case minV == nil && maxV == nil:
+		minVersion, maxVersion = defaultVersion, defaultVersion
+	case minV == nil:
+		minVersion, maxVersion = maxV, maxV
+	case maxV == nil:
+		minVersion, maxVersion = minV, defaultVersion
+		if maxVersion.LessThan(minVersion) {
+			maxVersion = minVersion
+		}
+	default:
+		minVersion, maxVersion = minV, maxV
+	}
+}
+
+// Find returns the deprecation for the apiVersion and kind if there is one.
+func Find(apiVersion, kind string) (*Deprecation, bool) {
+	for i := range deprecations {
+		if deprecations[i].Kind == kind && deprecations[i].GroupVersion() == apiVersion {
+			return &deprecations[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// ObjectAPIVersion checks the API version of the rendered object.
+func ObjectAPIVersion(moduleName string, object storage.StoreObject) *errors.LintRuleError {
+	return Check(
+		object.Identity(),
+		moduleName,
+		object.Unstructured.GetAPIVersion(),
+		object.Unstructured.GetKind(),
+	)
+}
+
+// Check returns a lint error if the API is deprecated or removed in the supported Kubernetes versions range.
+func Check(objectID, moduleName, apiVersion, kind string) *errors.LintRuleError {
+	d, ok := Find(apiVersion, kind)
+	if !ok {
+		return nil
+	}
+
+	if d.RemovedIn != "" && !maxVersion.LessThan(version.MustParseGeneric(d.RemovedIn)) {
+		return errors.NewLintRuleError(
+			ID,
+			objectID,
+			moduleName,
+			apiVersion,
+			"%s %s is removed in Kubernetes %s%s",
+			apiVersion, kind, d.RemovedIn, d.replacementHint(),
+		)
+	}
+
+	if !maxVersion.LessThan(version.MustParseGeneric(d.DeprecatedIn)) {
+		return errors.NewLintRuleError(
+			DeprecatedID,
+			objectID,
+			moduleName,
+			apiVersion,
+			"%s %s is deprecated since Kubernetes %s and will be removed in %s%s",
+			apiVersion, kind, d.DeprecatedIn, d.RemovedIn, d.replacementHint(),
+		)
+	}
+
+	return nil
+}
+
+func (d *Deprecation) replacementHint() string {
+	if d.Replacement == "" {
+		return ", the API has no replacement"
+	}
+
+	hint := strings.Builder{}
+	hint.WriteString(fmt.Sprintf(", use %q", d.Replacement))
+	if d.ReplacementIn != "" && minVersion.LessThan(version.MustParseGeneric(d.ReplacementIn)) {
+		hint.WriteString(fmt.Sprintf(
+			" (served since Kubernetes %s, check .Capabilities.APIVersions to support older versions)",
+			d.ReplacementIn,
+		))
+	}
+
+	return hint.String()
+}
@@ -0,0 +1,91 @@
+package apiversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		minV, maxV string
+		apiVersion string
+		kind       string
+		wantID     string
+		wantText   string
+	}{
+		{
+			name:       "current API",
+			minV:       "1.25",
+			maxV:       "1.30",
+			apiVersion: "apps/v1",
+			kind:       "Deployment",
+		},
+		{
+			name:       "removed API",
+			minV:       "1.25",
+			maxV:       "1.30",
+			apiVersion: "policy/v1beta1",
+			kind:       "PodDisruptionBudget",
+			wantID:     ID,
+			wantText:   `policy/v1beta1 PodDisruptionBudget is removed in Kubernetes 1.25, use "policy/v1"`,
+		},
+		{
+			name:       "removed API without replacement",
+			minV:       "1.25",
+			maxV:       "1.30",
+			apiVersion: "policy/v1beta1",
+			kind:       "PodSecurityPolicy",
+			wantID:     ID,
+			wantText:   `policy/v1beta1 PodSecurityPolicy is removed in Kubernetes 1.25, the API has no replacement`,
+		},
+		{
+			name:       "deprecated API",
+			minV:       "1.27",
+			maxV:       "1.30",
+			apiVersion: "flowcontrol.apiserver.k8s.io/v1beta3",
+			kind:       "FlowSchema",
+			wantID:     DeprecatedID,
+			wantText: `flowcontrol.apiserver.k8s.io/v1beta3 FlowSchema is deprecated since Kubernetes 1.29 and will be removed in 1.32, ` +
+				`use "flowcontrol.apiserver.k8s.io/v1" (served since Kubernetes 1.29, check .Capabilities.APIVersions to support older versions)`,
+		},
+		{
+			name:       "deprecated after supported range",
+			minV:       "1.25",
+			maxV:       "1.28",
+			apiVersion: "flowcontrol.apiserver.k8s.io/v1beta3",
+			kind:       "FlowSchema",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSupportedVersions(version.MustParseGeneric(tt.minV), version.MustParseGeneric(tt.maxV))
+
+			lerr := Check("object", "module", tt.apiVersion, tt.kind)
+			if tt.wantID == "" {
+				assert.Nil(t, lerr)
+				return
+			}
+
+			require.NotNil(t, lerr)
+			assert.Equal(t, tt.wantID, lerr.ID)
+			assert.Equal(t, tt.wantText, lerr.Text)
+		})
+	}
+}
+
+func TestServedGroupVersions(t *testing.T) {
+	served := ServedGroupVersions(version.MustParseGeneric("1.26"))
+	assert.Contains(t, served, "storage.k8s.io/v1beta1")
+	assert.Contains(t, served, "flowcontrol.apiserver.k8s.io/v1beta2")
+	assert.NotContains(t, served, "flowcontrol.apiserver.k8s.io/v1beta1")
+	assert.NotContains(t, served, "flowcontrol.apiserver.k8s.io/v1")
+
+	served = ServedGroupVersions(version.MustParseGeneric("1.27"))
+	assert.NotContains(t, served, "storage.k8s.io/v1beta1")
+	assert.Contains(t, served, "storage.k8s.io/v1")
+}
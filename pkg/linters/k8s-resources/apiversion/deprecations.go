@@ -0,0 +1,176 @@
+package apiversion
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Deprecation describes the lifecycle of a built-in API group/version/kind.
+type Deprecation struct {
+	Group   string
+	Version string
+	Kind    string
+
+	// DeprecatedIn is the Kubernetes version the API is deprecated in
+	DeprecatedIn string
+	// RemovedIn is the first Kubernetes version the API is no longer served in
+	RemovedIn string
+
+	// Replacement is the apiVersion to migrate to, empty if the API has no replacement
+	Replacement string
+	// ReplacementIn is the first Kubernetes version serving the replacement
+	ReplacementIn string
+}
+
+// GroupVersion returns the apiVersion of the deprecated API.
+func (d *Deprecation) GroupVersion() string {
+	if d.Group == "" {
+		return d.Version
+	}
+	return d.Group + "/" + d.Version
+}
+
+// https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecations = []Deprecation{
+	// v1.16
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "networking.k8s.io/v1", ReplacementIn: "1.8"},
+	{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.11", RemovedIn: "1.16"},
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta1", Kind: "ControllerRevision", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+	{Group: "apps", Version: "v1beta2", Kind: "ControllerRevision", DeprecatedIn: "1.9", RemovedIn: "1.16", Replacement: "apps/v1", ReplacementIn: "1.9"},
+
+	// v1.22
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1", ReplacementIn: "1.8"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1", ReplacementIn: "1.8"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1", ReplacementIn: "1.8"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "rbac.authorization.k8s.io/v1", ReplacementIn: "1.8"},
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "scheduling.k8s.io/v1", ReplacementIn: "1.14"},
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "apiextensions.k8s.io/v1", ReplacementIn: "1.16"},
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "apiregistration.k8s.io/v1", ReplacementIn: "1.10"},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1", ReplacementIn: "1.16"},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration", DeprecatedIn: "1.16", RemovedIn: "1.22", Replacement: "admissionregistration.k8s.io/v1", ReplacementIn: "1.16"},
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "certificates.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease", DeprecatedIn: "1.14", RemovedIn: "1.22", Replacement: "coordination.k8s.io/v1", ReplacementIn: "1.14"},
+	{Group: "authentication.k8s.io", Version: "v1beta1", Kind: "TokenReview", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "authentication.k8s.io/v1", ReplacementIn: "1.6"},
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "LocalSubjectAccessReview", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "authorization.k8s.io/v1", ReplacementIn: "1.6"},
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SelfSubjectAccessReview", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "authorization.k8s.io/v1", ReplacementIn: "1.6"},
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SubjectAccessReview", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "authorization.k8s.io/v1", ReplacementIn: "1.6"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode", DeprecatedIn: "1.17", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1", ReplacementIn: "1.17"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1", ReplacementIn: "1.6"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment", DeprecatedIn: "1.19", RemovedIn: "1.22", Replacement: "storage.k8s.io/v1", ReplacementIn: "1.13"},
+
+	// v1.25
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "batch/v1", ReplacementIn: "1.21"},
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "discovery.k8s.io/v1", ReplacementIn: "1.21"},
+	{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event", DeprecatedIn: "1.19", RemovedIn: "1.25", Replacement: "events.k8s.io/v1", ReplacementIn: "1.19"},
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.22", RemovedIn: "1.25", Replacement: "autoscaling/v2", ReplacementIn: "1.23"},
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "1.21", RemovedIn: "1.25", Replacement: "policy/v1", ReplacementIn: "1.21"},
+	{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.21", RemovedIn: "1.25"},
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass", DeprecatedIn: "1.20", RemovedIn: "1.25", Replacement: "node.k8s.io/v1", ReplacementIn: "1.20"},
+
+	// v1.26
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "autoscaling/v2", ReplacementIn: "1.23"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.23", RemovedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+
+	// v1.27
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "1.24", RemovedIn: "1.27", Replacement: "storage.k8s.io/v1", ReplacementIn: "1.24"},
+
+	// v1.29
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.26", RemovedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+
+	// v1.32
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.29", RemovedIn: "1.32", Replacement: "flowcontrol.apiserver.k8s.io/v1", ReplacementIn: "1.29"},
+}
+
+// introducedIn contains Kubernetes versions that started serving built-in group/versions by default.
+// Group/versions not listed here (alpha APIs) are not enabled in a cluster by default.
+// A group/version is no longer served since the latest RemovedIn of its deprecated kinds.
+var introducedIn = map[string]string{
+	"v1":                                   "1.0",
+	"admissionregistration.k8s.io/v1":      "1.16",
+	"admissionregistration.k8s.io/v1beta1": "1.9",
+	"apiextensions.k8s.io/v1":              "1.16",
+	"apiextensions.k8s.io/v1beta1":         "1.7",
+	"apiregistration.k8s.io/v1":            "1.10",
+	"apiregistration.k8s.io/v1beta1":       "1.7",
+	"apps/v1":                              "1.9",
+	"apps/v1beta1":                         "1.6",
+	"apps/v1beta2":                         "1.8",
+	"authentication.k8s.io/v1":             "1.6",
+	"authentication.k8s.io/v1beta1":        "1.3",
+	"authorization.k8s.io/v1":              "1.6",
+	"authorization.k8s.io/v1beta1":         "1.3",
+	"autoscaling/v1":                       "1.2",
+	"autoscaling/v2":                       "1.23",
+	"autoscaling/v2beta1":                  "1.8",
+	"autoscaling/v2beta2":                  "1.12",
+	"batch/v1":                             "1.2",
+	"batch/v1beta1":                        "1.8",
+	"certificates.k8s.io/v1":               "1.19",
+	"certificates.k8s.io/v1beta1":          "1.4",
+	"coordination.k8s.io/v1":               "1.14",
+	"coordination.k8s.io/v1beta1":          "1.12",
+	"discovery.k8s.io/v1":                  "1.21",
+	"discovery.k8s.io/v1beta1":             "1.17",
+	"events.k8s.io/v1":                     "1.19",
+	"events.k8s.io/v1beta1":                "1.8",
+	"extensions/v1beta1":                   "1.2",
+	"flowcontrol.apiserver.k8s.io/v1":      "1.29",
+	"flowcontrol.apiserver.k8s.io/v1beta1": "1.20",
+	"flowcontrol.apiserver.k8s.io/v1beta2": "1.23",
+	"flowcontrol.apiserver.k8s.io/v1beta3": "1.26",
+	"networking.k8s.io/v1":                 "1.8",
+	"networking.k8s.io/v1beta1":            "1.14",
+	"node.k8s.io/v1":                       "1.20",
+	"node.k8s.io/v1beta1":                  "1.14",
+	"policy/v1":                            "1.21",
+	"policy/v1beta1":                       "1.5",
+	"rbac.authorization.k8s.io/v1":         "1.8",
+	"rbac.authorization.k8s.io/v1beta1":    "1.6",
+	"scheduling.k8s.io/v1":                 "1.14",
+	"scheduling.k8s.io/v1beta1":            "1.11",
+	"storage.k8s.io/v1":                    "1.6",
+	"storage.k8s.io/v1beta1":               "1.6",
+}
+
+// ServedGroupVersions returns sorted built-in group/versions served by default by the Kubernetes version.
+func ServedGroupVersions(kubeVersion *version.Version) []string {
+	removedIn := make(map[string]*version.Version)
+	for i := range deprecations {
+		gv := deprecations[i].GroupVersion()
+		removed := version.MustParseGeneric(deprecations[i].RemovedIn)
+		if latest, ok := removedIn[gv]; !ok || latest.LessThan(removed) {
+			removedIn[gv] = removed
+		}
+	}
+
+	result := make([]string, 0, len(introducedIn))
+	for gv, introduced := range introducedIn {
+		if kubeVersion.LessThan(version.MustParseGeneric(introduced)) {
+			continue
+		}
+		if removed, ok := removedIn[gv]; ok && !kubeVersion.LessThan(removed) {
+			continue
+		}
+		result = append(result, gv)
+	}
+	slices.Sort(result)
+
+	return result
+}
@@ -0,0 +1,244 @@
+package builtin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"embed"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	openapiv2 "github.com/google/gnostic-models/openapiv2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/apiversion"
+)
+
+const (
+	ID = "object-schema"
+)
+
+// schemas contains swagger.json files of Kubernetes releases without descriptions,
+// named by the minor version, e.g. v1.30.json.gz
+//
+//go:embed schemas/*.json.gz
+var schemas embed.FS
+
+// kindSchemas contains schemas of built-in kinds of a Kubernetes minor version
+type kindSchemas struct {
+	once  sync.Once
+	kinds map[schema.GroupVersionKind]proto.Schema
+	err   error
+}
+
+var (
+	bundledVersions = loadBundledVersions()
+
+	mu     sync.Mutex
+	loaded = make(map[string]*kindSchemas)
+)
+
+func loadBundledVersions() []*version.Version {
+	entries, err := schemas.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make([]*version.Version, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, version.MustParseGeneric(strings.TrimSuffix(entry.Name(), ".json.gz")))
+	}
+	slices.SortFunc(result, func(a, b *version.Version) int {
+		switch {
+		case a.LessThan(b):
+			return -1
+		case b.LessThan(a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return result
+}
+
+// schemaVersion returns the bundled minor version closest to the target Kubernetes version.
+// apiversion.DefaultKubernetesVersion is used if the target version is not set.
+func schemaVersion(kubeVersion string) string {
+	target, err := version.ParseGeneric(kubeVersion)
+	if err != nil {
+		target = version.MustParseGeneric(apiversion.DefaultKubernetesVersion)
+	}
+
+	result := bundledVersions[0]
+	for _, v := range bundledVersions {
+		if v.Major() < target.Major() || (v.Major() == target.Major() && v.Minor() <= target.Minor()) {
+			result = v
+		}
+	}
+
+	return fmt.Sprintf("v%d.%d", result.Major(), result.Minor())
+}
+
+// kinds returns schemas of built-in kinds of the bundled version, they are parsed on the first use
+func kinds(schemaVersion string) (map[schema.GroupVersionKind]proto.Schema, error) {
+	mu.Lock()
+	s, ok := loaded[schemaVersion]
+	if !ok {
+		s = &kindSchemas{}
+		loaded[schemaVersion] = s
+	}
+	mu.Unlock()
+
+	s.once.Do(func() {
+		s.kinds, s.err = parseSchemas(schemaVersion)
+	})
+
+	return s.kinds, s.err
+}
+
+func parseSchemas(schemaVersion string) (map[schema.GroupVersionKind]proto.Schema, error) {
+	content, err := schemas.ReadFile(path.Join("schemas", schemaVersion+".json.gz"))
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("schemas %s: %w", schemaVersion, err)
+	}
+	content, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("schemas %s: %w", schemaVersion, err)
+	}
+
+	doc, err := openapiv2.ParseDocument(content)
+	if err != nil {
+		return nil, fmt.Errorf("schemas %s: %w", schemaVersion, err)
+	}
+	models, err := proto.NewOpenAPIData(doc)
+	if err != nil {
+		return nil, fmt.Errorf("schemas %s: %w", schemaVersion, err)
+	}
+
+	result := make(map[schema.GroupVersionKind]proto.Schema)
+	for _, name := range models.ListModels() {
+		model := models.LookupModel(name)
+		for _, gvk := range groupVersionKinds(model) {
+			result[gvk] = model
+		}
+	}
+
+	return result, nil
+}
+
+// groupVersionKinds returns kinds from the x-kubernetes-group-version-kind extension of the model
+func groupVersionKinds(model proto.Schema) []schema.GroupVersionKind {
+	list, ok := model.GetExtensions()["x-kubernetes-group-version-kind"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var result []schema.GroupVersionKind
+	for _, item := range list {
+		gvk, ok := item.(map[any]any)
+		if !ok {
+			continue
+		}
+		group, _ := gvk["group"].(string)
+		v, _ := gvk["version"].(string)
+		kind, _ := gvk["kind"].(string)
+		if v == "" || kind == "" {
+			continue
+		}
+		result = append(result, schema.GroupVersionKind{Group: group, Version: v, Kind: kind})
+	}
+
+	return result
+}
+
+// Validate validates the built-in object against the OpenAPI schema of the Kubernetes release closest
+// to the target version, like `kubectl --validate=strict` does. Unknown fields, wrong types and missing
+// required fields are reported with their field path. Objects of kinds not served by the release are skipped.
+func Validate(moduleName, kubeVersion string, object storage.StoreObject) (result errors.LintRuleErrorsList) {
+	schemaVersion := schemaVersion(kubeVersion)
+	kinds, err := kinds(schemaVersion)
+	if err != nil {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			object.Identity(),
+			moduleName,
+			nil,
+			"Cannot load Kubernetes %s OpenAPI schemas: %s", schemaVersion, err,
+		))
+		return result
+	}
+
+	model, ok := kinds[object.Unstructured.GroupVersionKind()]
+	if !ok {
+		return result
+	}
+
+	for _, err := range validation.ValidateModel(object.Unstructured.Object, model, "") {
+		fieldPath, message := errorMessage(err)
+		result.Add(errors.NewLintRuleError(
+			ID,
+			object.Identity(),
+			moduleName,
+			fieldPath,
+			"%s", message,
+		))
+	}
+
+	return result
+}
+
+// errorMessage returns the field path and the message of the validation error.
+// The message contains the field path, errors with the same text are reported once per object.
+func errorMessage(err error) (string, string) {
+	var validationErr validation.ValidationError
+	if !stderrors.As(err, &validationErr) {
+		return "", err.Error()
+	}
+
+	fieldPath := strings.TrimPrefix(validationErr.Path, ".")
+	var message string
+	//nolint:errorlint // validation errors are not wrapped
+	switch e := validationErr.Err.(type) {
+	case validation.UnknownFieldError:
+		fieldPath = joinPath(fieldPath, e.Field)
+		message = "unknown field, it is dropped by the API server"
+	case validation.MissingRequiredFieldError:
+		fieldPath = joinPath(fieldPath, e.Field)
+		message = "required field is missing"
+	case validation.InvalidTypeError:
+		message = fmt.Sprintf("invalid type: got %q, expected %q", e.Actual, e.Expected)
+	case validation.InvalidObjectTypeError:
+		message = fmt.Sprintf("invalid value type %q", e.Type)
+	default:
+		message = validationErr.Err.Error()
+	}
+
+	if fieldPath == "" {
+		return fieldPath, message
+	}
+
+	return fieldPath, fmt.Sprintf("%s: %s", fieldPath, message)
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
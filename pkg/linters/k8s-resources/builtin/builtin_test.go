@@ -0,0 +1,87 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: "two"
+  template:
+    spec:
+      containers:
+        - image: registry.example.com/test
+          resorces:
+            limits:
+              memory: 100Mi
+`
+
+func TestSchemaVersion(t *testing.T) {
+	assert.Equal(t, "v1.30", schemaVersion(""))
+	assert.Equal(t, "v1.28", schemaVersion("v1.28.3"))
+	assert.Equal(t, "v1.26", schemaVersion("1.20"))
+	assert.Equal(t, "v1.31", schemaVersion("1.35"))
+}
+
+func TestValidate(t *testing.T) {
+	var object unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(deployment), &object.Object))
+
+	result := Validate("test", "1.30", storage.StoreObject{Unstructured: object})
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, `spec.replicas: invalid type: got "string", expected "integer"`)
+	assert.Contains(t, text, "spec.template.spec.containers[0].resorces: unknown field")
+	assert.Contains(t, text, "spec.template.spec.containers[0].name: required field is missing")
+	assert.Contains(t, text, "spec.selector: required field is missing")
+
+	object.SetKind("Example")
+	result = Validate("test", "1.30", storage.StoreObject{Unstructured: object})
+	assert.NoError(t, result.ConvertToError())
+}
+
+// description is stripped from the bundled schemas, but it is a field of JSONSchemaProps
+const crd = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tests.example.com
+spec:
+  group: example.com
+  names:
+    kind: Test
+    plural: tests
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          description: Test object
+          properties:
+            spec:
+              type: object
+              description: Test spec
+`
+
+func TestValidateSchemaDescription(t *testing.T) {
+	var object unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(crd), &object.Object))
+
+	for _, kubeVersion := range []string{"1.26", "1.27", "1.28", "1.29", "1.30", "1.31"} {
+		result := Validate("test", kubeVersion, storage.StoreObject{Unstructured: object})
+		assert.NoError(t, result.ConvertToError(), kubeVersion)
+	}
+}
@@ -0,0 +1,122 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "crd"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	install.Install(scheme)
+}
+
+// Validate runs the validation the API server runs on creating the apiextensions.k8s.io/v1 CustomResourceDefinition:
+// structural schemas, storage and served versions, printer columns and x-kubernetes-* extensions.
+// Every violation is reported with its field path.
+func Validate(moduleName, objectID string, content []byte) (result errors.LintRuleErrorsList) {
+	crd, err := decode(content)
+	if err != nil {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			objectID,
+			moduleName,
+			nil,
+			"Cannot parse CustomResourceDefinition: %s", err,
+		))
+		return result
+	}
+
+	for _, fieldErr := range validateCRD(crd) {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			objectID,
+			moduleName,
+			fieldErr.Field,
+			"%s", errorMessage(fieldErr),
+		))
+	}
+
+	return result
+}
+
+// errorMessage returns the field error without its bad value if the value is a whole struct or list.
+// The message contains the field path, errors with the same text are reported once per object.
+func errorMessage(fieldErr *field.Error) string {
+	var body string
+	switch fieldErr.BadValue.(type) {
+	case nil, string, bool, int, int32, int64, float64:
+		body = fieldErr.ErrorBody()
+	default:
+		body = fieldErr.Type.String()
+		if fieldErr.Detail != "" {
+			body = fmt.Sprintf("%s: %s", fieldErr.Type, fieldErr.Detail)
+		}
+	}
+
+	if fieldErr.Field == "" {
+		return body
+	}
+
+	return fmt.Sprintf("%s: %s", fieldErr.Field, body)
+}
+
+func decode(content []byte) (*apiextensions.CustomResourceDefinition, error) {
+	external := new(apiextensionsv1.CustomResourceDefinition)
+	if err := yaml.Unmarshal(content, external); err != nil {
+		return nil, err
+	}
+	scheme.Default(external)
+
+	crd := new(apiextensions.CustomResourceDefinition)
+	if err := scheme.Convert(external, crd, nil); err != nil {
+		return nil, err
+	}
+
+	return crd, nil
+}
+
+// validateCRD returns spec and metadata violations. Status is set by the API server, so it is not validated.
+func validateCRD(crd *apiextensions.CustomResourceDefinition) field.ErrorList {
+	var result field.ErrorList
+	for _, fieldErr := range validation.ValidateCustomResourceDefinition(context.Background(), crd) {
+		if strings.HasPrefix(fieldErr.Field, "status") {
+			continue
+		}
+		result = append(result, fieldErr)
+	}
+
+	served := false
+	for _, v := range crd.Spec.Versions {
+		served = served || v.Served
+	}
+	if len(crd.Spec.Versions) > 0 && !served {
+		result = append(result, field.Invalid(field.NewPath("spec", "versions"), servedVersions(crd), "must have at least one served version"))
+	}
+
+	return result
+}
+
+func servedVersions(crd *apiextensions.CustomResourceDefinition) string {
+	names := make([]string, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		names = append(names, fmt.Sprintf("%s (served: %t)", v.Name, v.Served))
+	}
+
+	return strings.Join(names, ", ")
+}
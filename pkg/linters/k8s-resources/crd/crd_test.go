@@ -0,0 +1,90 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: examples.deckhouse.io
+spec:
+  group: deckhouse.io
+  scope: Cluster
+  names:
+    plural: examples
+    singular: example
+    kind: Example
+  versions:
+    - name: v1alpha1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                replicas:
+                  type: integer
+      additionalPrinterColumns:
+        - name: Replicas
+          type: integer
+          jsonPath: .spec.replicas
+`
+
+const invalidCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: examples.deckhouse.io
+spec:
+  group: deckhouse.io
+  scope: Cluster
+  names:
+    plural: examples
+    singular: example
+    kind: Example
+  versions:
+    - name: v1alpha1
+      served: false
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              properties:
+                replicas:
+                  type: integer
+                  x-kubernetes-list-type: set
+      additionalPrinterColumns:
+        - name: Replicas
+          type: number-ish
+          jsonPath: .spec.replicas
+    - name: v1
+      served: false
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+`
+
+func TestValidate(t *testing.T) {
+	result := Validate("test", "crd", []byte(validCRD))
+	assert.NoError(t, result.ConvertToError())
+
+	result = Validate("test", "crd", []byte(invalidCRD))
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "spec.versions")
+	assert.Contains(t, text, "must have exactly one version marked as storage version")
+	assert.Contains(t, text, "must have at least one served version")
+	assert.Contains(t, text, "spec.versions[0].schema.openAPIV3Schema.properties[spec].type")
+	assert.Contains(t, text, "must be array if x-kubernetes-list-type is specified")
+	assert.NotContains(t, text, "apiextensions.CustomResourceDefinitionVersion")
+	assert.Contains(t, text, "spec.versions[0].additionalPrinterColumns[0].type")
+}
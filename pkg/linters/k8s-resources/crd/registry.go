@@ -0,0 +1,225 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/deckhouse/dmt/internal/logger"
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+// CustomResourceID is used for custom resources not matching the schema of their CustomResourceDefinition
+const CustomResourceID = "custom-resource"
+
+// Registry contains schemas of served versions of CustomResourceDefinitions
+type Registry struct {
+	schemas map[schema.GroupVersionKind]*versionSchema
+	hash    string
+}
+
+// versionSchema is the schema of a served CustomResourceDefinition version, validators are built on the first use
+type versionSchema struct {
+	// source is the file the CustomResourceDefinition is defined in
+	source string
+	props  *apiextensions.JSONSchemaProps
+
+	once       sync.Once
+	validator  validation.SchemaValidator
+	structural *structuralschema.Structural
+	err        error
+}
+
+// registry is used to validate rendered custom resources, no resources are validated if it is nil
+var registry *Registry
+
+// SetRegistry sets schemas rendered custom resources are validated against.
+func SetRegistry(r *Registry) {
+	registry = r
+}
+
+// NewRegistry loads CustomResourceDefinitions from yaml files in dirs and their subdirectories.
+// If several definitions serve the same version of a kind, the first one wins.
+// Documents which are not CustomResourceDefinitions or can't be parsed are skipped.
+func NewRegistry(dirs ...string) (*Registry, error) {
+	r := &Registry{schemas: make(map[schema.GroupVersionKind]*versionSchema)}
+
+	var digest bytes.Buffer
+	for _, dir := range dirs {
+		var files []string
+		err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// skip translated descriptions
+			if !d.IsDir() && filepath.Ext(path) == ".yaml" && !strings.HasPrefix(d.Name(), "doc-ru-") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("crd dir: %w", err)
+		}
+		slices.Sort(files)
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			digest.WriteString(file)
+			digest.Write(content)
+
+			if err := r.add(file, content); err != nil {
+				logger.WarnF("Cannot load CustomResourceDefinitions from %s: %s", file, err)
+			}
+		}
+	}
+
+	r.hash = storage.NewSHA256(digest.Bytes())
+
+	return r, nil
+}
+
+func (r *Registry) add(file string, content []byte) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		var doc json.RawMessage
+		err := decoder.Decode(&doc)
+		if stderrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var object struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}
+		if err := json.Unmarshal(doc, &object); err != nil || object.Kind != "CustomResourceDefinition" || object.APIVersion != "apiextensions.k8s.io/v1" {
+			continue
+		}
+
+		crd, err := decode(doc)
+		if err != nil {
+			return err
+		}
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+
+			versionValidation, err := apiextensions.GetSchemaForVersion(crd, version.Name)
+			if err != nil || versionValidation == nil || versionValidation.OpenAPIV3Schema == nil {
+				continue
+			}
+
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			if _, ok := r.schemas[gvk]; !ok {
+				r.schemas[gvk] = &versionSchema{source: file, props: versionValidation.OpenAPIV3Schema}
+			}
+		}
+	}
+}
+
+// Hash returns the hash of loaded files, linter results depending on the registry are cached by it.
+func (r *Registry) Hash() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.hash
+}
+
+// Validate validates the custom resource against the schema of its version.
+// Fields dropped by the API server because they are not in the schema are reported as well.
+// It returns false if the registry does not contain the schema.
+func (r *Registry) Validate(object *unstructured.Unstructured) (field.ErrorList, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	s, ok := r.schemas[object.GroupVersionKind()]
+	if !ok {
+		return nil, false
+	}
+
+	s.once.Do(func() {
+		s.validator, _, s.err = validation.NewSchemaValidator(s.props)
+		if s.err == nil {
+			s.structural, s.err = structuralschema.NewStructural(s.props)
+		}
+	})
+	if s.err != nil {
+		return field.ErrorList{field.InternalError(nil, fmt.Errorf("schema from %s: %w", s.source, s.err))}, true
+	}
+
+	// values are converted to JSON types the API server validates
+	content, err := toJSONObject(object.Object)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, err)}, true
+	}
+
+	result := validation.ValidateCustomResource(nil, content, s.validator)
+
+	pruned := pruning.PruneWithOptions(content, s.structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, path := range pruned {
+		result = append(result, field.Forbidden(field.NewPath(path), "field is not defined in the schema and is dropped by the API server"))
+	}
+
+	return result, true
+}
+
+func toJSONObject(object map[string]any) (map[string]any, error) {
+	content, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	if err := utiljson.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ValidateCustomResource reports violations of the schema of the rendered custom resource.
+func ValidateCustomResource(moduleName string, object storage.StoreObject) (result errors.LintRuleErrorsList) {
+	fieldErrs, ok := registry.Validate(&object.Unstructured)
+	if !ok {
+		return result
+	}
+
+	for _, fieldErr := range fieldErrs {
+		result.Add(errors.NewLintRuleError(
+			CustomResourceID,
+			object.Identity(),
+			moduleName,
+			fieldErr.Field,
+			"%s", errorMessage(fieldErr),
+		))
+	}
+
+	return result
+}
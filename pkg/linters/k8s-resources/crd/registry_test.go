@@ -0,0 +1,49 @@
+package crd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+const customResource = `
+apiVersion: deckhouse.io/v1alpha1
+kind: Example
+metadata:
+  name: example
+spec:
+  replicas: two
+  unknown: true
+  other: true
+`
+
+func TestValidateCustomResource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.yaml"), []byte(validCRD), 0o600))
+
+	r, err := NewRegistry(dir)
+	require.NoError(t, err)
+	SetRegistry(r)
+	defer SetRegistry(nil)
+
+	var object unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(customResource), &object.Object))
+
+	result := ValidateCustomResource("test", storage.StoreObject{Unstructured: object})
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "spec.replicas")
+	assert.Contains(t, text, "must be of type integer")
+	assert.Contains(t, text, "spec.unknown")
+	assert.Contains(t, text, "spec.other: Forbidden: field is not defined in the schema")
+
+	object.SetAPIVersion("deckhouse.io/v1")
+	result = ValidateCustomResource("test", storage.StoreObject{Unstructured: object})
+	assert.NoError(t, result.ConvertToError())
+}
@@ -24,20 +24,26 @@ import (
 	"strings"
 
 	"github.com/ghodss/yaml"
-	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 
 	"github.com/deckhouse/dmt/pkg/errors"
 	"github.com/deckhouse/dmt/pkg/linters/helm/rules"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/apiversion"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/crd"
 )
 
 var (
 	sep = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
 )
 
-func shouldSkipCrd(name string) bool {
-	return !strings.Contains(name, "deckhouse.io")
+type crdsObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
 }
 
+// CrdsModuleRule checks API versions of all objects in the crds folder and validates CustomResourceDefinitions
 func CrdsModuleRule(name, path string) errors.LintRuleErrorsList {
 	var lintRuleErrorsList errors.LintRuleErrorsList
 	_ = filepath.Walk(path, func(path string, _ os.FileInfo, _ error) error {
@@ -58,30 +64,34 @@ func CrdsModuleRule(name, path string) errors.LintRuleErrorsList {
 			}
 
 			d = strings.TrimSpace(d)
-			var crd v1beta1.CustomResourceDefinition
+			var object crdsObject
 
-			err = yaml.Unmarshal([]byte(d), &crd)
+			err = yaml.Unmarshal([]byte(d), &object)
 			if err != nil {
 				lintRuleErrorsList.Add(errors.NewLintRuleError(
 					rules.ID,
-					"module = "+name,
+					fmt.Sprintf("module = %s ; file = %s", name, path),
+					name,
 					err.Error(),
 					"Can't parse manifests in %s folder", rules.CrdsDir,
 				))
+				continue
 			}
 
-			if shouldSkipCrd(crd.Name) {
+			if object.Kind == "" {
 				continue
 			}
 
-			if crd.APIVersion != "apiextensions.k8s.io/v1" {
-				lintRuleErrorsList.Add(errors.NewLintRuleError(
-					rules.ID,
-					d,
-					fmt.Sprintf("kind = %s ; name = %s ; module = %s ; file = %s", crd.Kind, crd.Name, name, path),
-					crd.APIVersion,
-					`CRD specified using deprecated api version, wanted "apiextensions.k8s.io/v1"`,
-				))
+			objectID := fmt.Sprintf("kind = %s ; name = %s ; module = %s ; file = %s", object.Kind, object.Metadata.Name, name, path)
+			lintRuleErrorsList.Add(apiversion.Check(
+				objectID,
+				name,
+				object.APIVersion,
+				object.Kind,
+			))
+
+			if object.Kind == "CustomResourceDefinition" && object.APIVersion == "apiextensions.k8s.io/v1" {
+				lintRuleErrorsList.Merge(crd.Validate(name, objectID, []byte(d)))
 			}
 		}
 		return nil
@@ -0,0 +1,39 @@
+package k8sresources
+
+import (
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	// DuplicateID is used for duplicate objects with the error policy
+	DuplicateID = "duplicate-object"
+	// DuplicateWarningID is used for duplicate objects with the warn policy, it is always a warning
+	DuplicateWarningID = "duplicate-object-warning"
+)
+
+func duplicateObjects(m *module.Module) (result errors.LintRuleErrorsList) {
+	if m.GetObjectStore() == nil {
+		return result
+	}
+
+	for _, duplicate := range m.GetObjectStore().Duplicates() {
+		id := DuplicateID
+		if duplicate.Policy == storage.DuplicateWarn {
+			id = DuplicateWarningID
+		}
+
+		object := m.GetObjectStore().Get(duplicate.Index)
+		result.Add(errors.NewLintRuleError(
+			id,
+			object.Identity(),
+			m.GetName(),
+			nil,
+			"Object is rendered more than once: in %s and %s",
+			duplicate.Paths[0], duplicate.Paths[1],
+		))
+	}
+
+	return result
+}
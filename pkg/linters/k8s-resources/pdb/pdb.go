@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"slices"
 
-	appsv1 "k8s.io/api/apps/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -29,7 +28,6 @@ import (
 	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/internal/storage"
 	"github.com/deckhouse/dmt/pkg/errors"
-	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/vpa"
 )
 
 type nsLabelSelector struct {
@@ -57,15 +55,7 @@ func ControllerMustHavePDB(md *module.Module) (result errors.LintRuleErrorsList)
 	pdbSelectors, lerr := collectPDBSelectors(md)
 	result.Merge(lerr)
 
-	for _, object := range md.GetObjectStore().Storage {
-		if !vpa.IsPodController(object.Unstructured.GetKind()) {
-			continue
-		}
-
-		if isPodControllerDaemonSet(object.Unstructured.GetKind()) {
-			continue
-		}
-
+	for _, object := range md.GetObjectStore().ByKind("Deployment", "StatefulSet") {
 		lerr := ensurePDBIsPresent(md, pdbSelectors, object)
 		result.Add(lerr)
 	}
@@ -73,10 +63,6 @@ func ControllerMustHavePDB(md *module.Module) (result errors.LintRuleErrorsList)
 	return result
 }
 
-func isPodControllerDaemonSet(kind string) bool {
-	return kind == "DaemonSet"
-}
-
 // DaemonSetMustNotHavePDB adds linting errors if there are pods from DaemonSets which are covered
 // by a PodDisruptionBudget
 func DaemonSetMustNotHavePDB(md *module.Module) (result errors.LintRuleErrorsList) {
@@ -87,15 +73,7 @@ func DaemonSetMustNotHavePDB(md *module.Module) (result errors.LintRuleErrorsLis
 	pdbSelectors, lerr := collectPDBSelectors(md)
 	result.Merge(lerr)
 
-	for _, object := range md.GetObjectStore().Storage {
-		if !vpa.IsPodController(object.Unstructured.GetKind()) {
-			continue
-		}
-
-		if !isPodControllerDaemonSet(object.Unstructured.GetKind()) {
-			continue
-		}
-
+	for _, object := range md.GetObjectStore().ByKind("DaemonSet") {
 		lerr := ensurePDBIsNotPresent(md, pdbSelectors, object)
 		result.Add(lerr)
 	}
@@ -105,11 +83,7 @@ func DaemonSetMustNotHavePDB(md *module.Module) (result errors.LintRuleErrorsLis
 
 // collectPDBSelectors collects selectors for matching pods
 func collectPDBSelectors(md *module.Module) (selectors []nsLabelSelector, result errors.LintRuleErrorsList) {
-	for _, object := range md.GetObjectStore().Storage {
-		if object.Unstructured.GetKind() != "PodDisruptionBudget" {
-			continue
-		}
-
+	for _, object := range md.GetObjectStore().ByKind("PodDisruptionBudget") {
 		labelSelector, lerr := parsePDBSelector(md, object)
 		if lerr != nil {
 			result.Add(lerr)
@@ -226,36 +200,16 @@ func parsePDBSelector(md *module.Module, pdbObj storage.StoreObject) (labels.Sel
 }
 
 func parsePodControllerLabels(object storage.StoreObject) (map[string]string, error) {
-	content := object.Unstructured.UnstructuredContent()
-	converter := runtime.DefaultUnstructuredConverter
-	kind := object.Unstructured.GetKind()
-
-	switch kind {
-	case "Deployment":
-		deployment := new(appsv1.Deployment)
-		err := converter.FromUnstructured(content, deployment)
-		if err != nil {
-			return nil, err
-		}
-		return deployment.Spec.Template.Labels, nil
-
-	case "DaemonSet":
-		daemonSet := new(appsv1.DaemonSet)
-		err := converter.FromUnstructured(content, daemonSet)
-		if err != nil {
-			return nil, err
-		}
-		return daemonSet.Spec.Template.Labels, nil
-
-	case "StatefulSet":
-		statefulSet := new(appsv1.StatefulSet)
-		err := converter.FromUnstructured(content, statefulSet)
-		if err != nil {
-			return nil, err
-		}
-		return statefulSet.Spec.Template.Labels, nil
-
+	switch kind := object.Unstructured.GetKind(); kind {
+	case "Deployment", "DaemonSet", "StatefulSet":
 	default:
 		return nil, fmt.Errorf("object of kind %s is not a pod controller", kind)
 	}
+
+	template, err := object.PodTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	return template.Labels, nil
 }
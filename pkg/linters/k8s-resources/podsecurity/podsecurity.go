@@ -0,0 +1,138 @@
+package podsecurity
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "pod-security"
+)
+
+var (
+	// DefaultLevel is enforced in namespaces without the `pod-security.kubernetes.io/enforce` label
+	// and in namespaces not rendered by the module
+	DefaultLevel = api.LevelBaseline
+	// SkipPodSecurityChecks contains `namespace/Kind/name` patterns of objects not checked
+	SkipPodSecurityChecks []string
+
+	evaluator = newEvaluator()
+)
+
+func newEvaluator() policy.Evaluator {
+	e, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// SetDefaultLevel sets the level enforced in namespaces without the level label, the baseline level is used
+// if the level is empty or invalid. Levels are validated when the config is loaded.
+func SetDefaultLevel(level string) {
+	l, err := api.ParseLevel(level)
+	if err != nil {
+		l = api.LevelBaseline
+	}
+	DefaultLevel = l
+}
+
+// ObjectsMustMatchPodSecurityLevel evaluates pod templates of the module objects against the Pod Security Standards
+// level of their namespace: privileged containers, capabilities, privilege escalation, seccomp and AppArmor profiles,
+// host namespaces, ports and paths, volume types, procMount, sysctls and running as non-root.
+// The level is taken from the `pod-security.kubernetes.io/enforce` label of the Namespace object rendered by the module.
+func ObjectsMustMatchPodSecurityLevel(m *module.Module) (result errors.LintRuleErrorsList) {
+	policies := make(map[string]api.LevelVersion)
+	defaultPolicy := api.Policy{Enforce: api.LevelVersion{Level: DefaultLevel, Version: targetVersion(m.GetKubeVersion())}}
+
+	for _, namespace := range m.GetObjectStore().ByKind("Namespace") {
+		p, errs := api.PolicyToEvaluate(namespace.Unstructured.GetLabels(), defaultPolicy)
+		for _, err := range errs {
+			result.Add(errors.NewLintRuleError(
+				ID,
+				namespace.Identity(),
+				m.GetName(),
+				err.Field,
+				"Invalid Pod Security Standards label: %s", err.ErrorBody(),
+			))
+		}
+		policies[namespace.Unstructured.GetName()] = p.Enforce
+	}
+
+	for _, object := range m.GetStorage() {
+		if object.Unstructured.GetKind() == "PodTemplate" {
+			// pod templates do not run pods
+			continue
+		}
+
+		namespace := object.Unstructured.GetNamespace()
+		if namespace == "" {
+			namespace = m.GetNamespace()
+		}
+		index := storage.GetResourceIndex(object)
+		index.Namespace = namespace
+		if index.MatchesAny(SkipPodSecurityChecks) {
+			continue
+		}
+
+		levelVersion, ok := policies[namespace]
+		if !ok {
+			levelVersion = defaultPolicy.Enforce
+		}
+
+		result.Merge(evaluate(m.GetName(), object, levelVersion))
+	}
+
+	return result
+}
+
+func evaluate(moduleName string, object storage.StoreObject, levelVersion api.LevelVersion) (result errors.LintRuleErrorsList) {
+	if levelVersion.Level == api.LevelPrivileged {
+		return result
+	}
+
+	template, err := object.PodTemplate()
+	if err != nil || template == nil {
+		// conversion errors are reported by other rules
+		return result
+	}
+
+	for _, check := range evaluator.EvaluatePod(levelVersion, &template.ObjectMeta, &template.Spec) {
+		if check.Allowed {
+			continue
+		}
+
+		violation := check.ForbiddenReason
+		if check.ForbiddenDetail != "" {
+			violation = fmt.Sprintf("%s (%s)", check.ForbiddenReason, check.ForbiddenDetail)
+		}
+
+		result.Add(errors.NewLintRuleError(
+			ID,
+			object.Identity(),
+			moduleName,
+			levelVersion.String(),
+			"Pod violates PodSecurity %q: %s", levelVersion.String(), violation,
+		))
+	}
+
+	return result
+}
+
+// targetVersion returns the Pod Security Standards version of the target Kubernetes version, the latest one if it is not set
+func targetVersion(kubeVersion string) api.Version {
+	v, err := version.ParseGeneric(kubeVersion)
+	if err != nil {
+		return api.LatestVersion()
+	}
+
+	return api.MajorMinorVersion(int(v.Major()), int(v.Minor()))
+}
@@ -0,0 +1,150 @@
+package podsecurity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/pod-security-admission/api"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  namespace: d8-test
+spec:
+  template:
+    spec:
+      hostPID: true
+      containers:
+        - name: test
+          image: registry.example.com/test
+          securityContext:
+            capabilities:
+              add: ["NET_ADMIN"]
+`
+
+func TestEvaluate(t *testing.T) {
+	var object unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(deployment), &object.Object))
+	storeObject := storage.StoreObject{Unstructured: object}
+
+	result := evaluate("test", storeObject, api.LevelVersion{Level: api.LevelBaseline, Version: api.LatestVersion()})
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, `Pod violates PodSecurity "baseline:latest": host namespaces (hostPID=true)`)
+	assert.Contains(t, text, `non-default capabilities (container "test" must not include "NET_ADMIN" in securityContext.capabilities.add)`)
+	assert.NotContains(t, text, "seccompProfile")
+
+	result = evaluate("test", storeObject, api.LevelVersion{Level: api.LevelRestricted, Version: api.MajorMinorVersion(1, 30)})
+	text = result.ConvertToError().Error()
+	assert.Contains(t, text, `Pod violates PodSecurity "restricted:v1.30": allowPrivilegeEscalation != false`)
+	assert.Contains(t, text, "seccompProfile")
+
+	result = evaluate("test", storeObject, api.LevelVersion{Level: api.LevelPrivileged, Version: api.LatestVersion()})
+	assert.NoError(t, result.ConvertToError())
+}
+
+func deploymentIn(namespace, name string) string {
+	return `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ` + name + `
+  namespace: ` + namespace + `
+spec:
+  selector:
+    matchLabels: {app: ` + name + `}
+  template:
+    metadata:
+      labels: {app: ` + name + `}
+    spec:
+      hostPID: true
+      containers:
+        - name: test
+          image: registry.example.com/test
+`
+}
+
+func namespaceWith(name, level string) string {
+	result := `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + name + `
+`
+	if level != "" {
+		result += `  labels:
+    pod-security.kubernetes.io/enforce: "` + level + `"
+`
+	}
+
+	return result
+}
+
+func TestObjectsMustMatchPodSecurityLevel(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		module.ModuleYamlFilename: "name: test-psa\nnamespace: d8-test-psa\n",
+		"openapi/values.yaml":     "type: object\n",
+		"templates/namespaces.yaml": namespaceWith("d8-privileged", "privileged") +
+			namespaceWith("d8-restricted", "restricted") +
+			namespaceWith("d8-unlabeled", "") +
+			namespaceWith("d8-invalid", "unknown"),
+		"templates/deployments.yaml": deploymentIn("d8-privileged", "privileged") +
+			deploymentIn("d8-restricted", "restricted") +
+			deploymentIn("d8-unlabeled", "unlabeled") +
+			deploymentIn("d8-test-psa", "module") +
+			deploymentIn("d8-test-psa", "skipped"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	m, err := module.NewModule(dir, nil)
+	require.NoError(t, err)
+
+	defaultLevel := DefaultLevel
+	t.Cleanup(func() {
+		DefaultLevel = defaultLevel
+		SkipPodSecurityChecks = nil
+	})
+	SetDefaultLevel("baseline")
+	SkipPodSecurityChecks = []string{"d8-test-psa/Deployment/skipped"}
+
+	result := ObjectsMustMatchPodSecurityLevel(m)
+	text := result.ConvertToError().Error()
+
+	// the enforce label of the rendered Namespace takes precedence over the default level
+	assert.NotContains(t, text, "name = privileged ;")
+	assert.Contains(t, text, `Pod violates PodSecurity "restricted:latest": host namespaces (hostPID=true)`)
+	assert.Contains(t, text, `Pod violates PodSecurity "restricted:latest": allowPrivilegeEscalation != false`)
+
+	// namespaces without the label and not rendered by the module use the default level
+	assert.Contains(t, text, "kind = Deployment ; name = unlabeled ; namespace = d8-unlabeled")
+	assert.Contains(t, text, "kind = Deployment ; name = module ; namespace = d8-test-psa")
+	assert.Contains(t, text, `Pod violates PodSecurity "baseline:latest": host namespaces (hostPID=true)`)
+
+	// invalid labels are reported and the default level is used
+	assert.Contains(t, text, "Invalid Pod Security Standards label")
+	assert.Contains(t, text, "kind = Namespace ; name = d8-invalid")
+
+	assert.NotContains(t, text, "name = skipped ;")
+
+	SetDefaultLevel("privileged")
+	result = ObjectsMustMatchPodSecurityLevel(m)
+	text = result.ConvertToError().Error()
+	assert.Contains(t, text, "name = restricted ;")
+	assert.NotContains(t, text, "name = unlabeled ;")
+	assert.NotContains(t, text, "name = module ;")
+}
@@ -7,7 +7,11 @@ import (
 	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/pkg/config"
 	"github.com/deckhouse/dmt/pkg/errors"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/apiversion"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/builtin"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/crd"
 	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/pdb"
+	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/podsecurity"
 	rbacproxy "github.com/deckhouse/dmt/pkg/linters/k8s-resources/rbac-proxy"
 	"github.com/deckhouse/dmt/pkg/linters/k8s-resources/vpa"
 )
@@ -27,8 +31,11 @@ var Cfg *config.K8SResourcesSettings
 
 func New(cfg *config.K8SResourcesSettings) *Object {
 	Cfg = cfg
+	apiversion.SetSupportedVersions(cfg.SupportedKubernetesVersions.MinVersion(), cfg.SupportedKubernetesVersions.MaxVersion())
 	pdb.SkipPDBChecks = cfg.SkipPDBChecks
 	vpa.SkipVPAChecks = cfg.SkipVPAChecks
+	podsecurity.SkipPodSecurityChecks = cfg.SkipPodSecurityChecks
+	podsecurity.SetDefaultLevel(cfg.PodSecurityLevel)
 	rbacproxy.SkipKubeRbacProxyChecks = cfg.SkipKubeRbacProxyChecks
 
 	return &Object{
@@ -38,18 +45,24 @@ func New(cfg *config.K8SResourcesSettings) *Object {
 	}
 }
 
-func (*Object) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+func (o *Object) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
 	if m == nil {
 		return result, err
 	}
 
+	result.Merge(duplicateObjects(m))
 	result.Merge(rbacproxy.NamespaceMustContainKubeRBACProxyCA(m.GetObjectStore()))
 	result.Merge(vpa.ControllerMustHaveVPA(m))
 	result.Merge(pdb.ControllerMustHavePDB(m))
 	result.Merge(pdb.DaemonSetMustNotHavePDB(m))
+	result.Merge(podsecurity.ObjectsMustMatchPodSecurityLevel(m))
+	result.Merge(selectorRules(m.GetName(), m.GetObjectStore(), o.cfg.ExternalSelectors))
 
 	for _, object := range m.GetStorage() {
 		result.Merge(applyContainerRules(object))
+		result.Add(apiversion.ObjectAPIVersion(m.GetName(), object))
+		result.Merge(crd.ValidateCustomResource(m.GetName(), object))
+		result.Merge(builtin.Validate(m.GetName(), m.GetKubeVersion(), object))
 	}
 
 	if isExistsOnFilesystem(m.GetPath(), CrdsDir) {
@@ -23,7 +23,6 @@ func applyContainerRules(object storage.StoreObject) (result errors.LintRuleErro
 
 	result.Add(objectRecommendedLabels(object))
 	result.Add(namespaceLabels(object))
-	result.Add(objectAPIVersion(object))
 	result.Add(objectPriorityClass(object))
 	result.Add(objectDNSPolicy(object))
 	result.Add(objectSecurityContext(object))
@@ -81,41 +80,6 @@ func namespaceLabels(object storage.StoreObject) *errors.LintRuleError {
 		`Namespace object does not have the label "prometheus.deckhouse.io/rules-watcher-enabled"`)
 }
 
-func newAPIVersionError(wanted, version, objectID string) *errors.LintRuleError {
-	if version != wanted {
-		return errors.NewLintRuleError(
-			ID,
-			objectID,
-			version,
-			nil,
-			"Object defined using deprecated api version, wanted %q", wanted,
-		)
-	}
-	return nil
-}
-
-func objectAPIVersion(object storage.StoreObject) *errors.LintRuleError {
-	kind := object.Unstructured.GetKind()
-	version := object.Unstructured.GetAPIVersion()
-
-	switch kind {
-	case "Role", "RoleBinding", "ClusterRole", "ClusterRoleBinding":
-		return newAPIVersionError("rbac.authorization.k8s.io/v1", version, object.Identity())
-	case "Deployment", "DaemonSet", "StatefulSet":
-		return newAPIVersionError("apps/v1", version, object.Identity())
-	case "Ingress":
-		return newAPIVersionError("networking.k8s.io/v1", version, object.Identity())
-	case "PriorityClass":
-		return newAPIVersionError("scheduling.k8s.io/v1", version, object.Identity())
-	case "PodSecurityPolicy":
-		return newAPIVersionError("policy/v1beta1", version, object.Identity())
-	case "NetworkPolicy":
-		return newAPIVersionError("networking.k8s.io/v1", version, object.Identity())
-	default:
-		return nil
-	}
-}
-
 func newConvertError(object storage.StoreObject, err error) *errors.LintRuleError {
 	return errors.NewLintRuleError(
 		ID,
@@ -126,6 +90,19 @@ func newConvertError(object storage.StoreObject, err error) *errors.LintRuleErro
 	)
 }
 
+// podTemplate returns the pod template of the object, it is empty for kinds without a pod template.
+func podTemplate(object storage.StoreObject) (*v1.PodTemplateSpec, *errors.LintRuleError) {
+	template, err := object.PodTemplate()
+	if err != nil {
+		return nil, newConvertError(object, err)
+	}
+	if template == nil {
+		return &v1.PodTemplateSpec{}, nil
+	}
+
+	return template, nil
+}
+
 func objectRevisionHistoryLimit(object storage.StoreObject) *errors.LintRuleError {
 	if object.Unstructured.GetKind() == "Deployment" {
 		converter := runtime.DefaultUnstructuredConverter
@@ -169,43 +146,19 @@ func objectRevisionHistoryLimit(object storage.StoreObject) *errors.LintRuleErro
 }
 
 func objectPriorityClass(object storage.StoreObject) *errors.LintRuleError {
-	kind := object.Unstructured.GetKind()
-	converter := runtime.DefaultUnstructuredConverter
-
-	var priorityClass string
-
-	switch kind {
-	case "Deployment":
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		priorityClass = deployment.Spec.Template.Spec.PriorityClassName
-	case "DaemonSet":
-		daemonset := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), daemonset)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		priorityClass = daemonset.Spec.Template.Spec.PriorityClassName
-	case "StatefulSet":
-		statefulset := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), statefulset)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		priorityClass = statefulset.Spec.Template.Spec.PriorityClassName
+	switch object.Unstructured.GetKind() {
+	case "Deployment", "DaemonSet", "StatefulSet":
 	default:
 		return nil
 	}
 
+	template, lerr := podTemplate(object)
+	if lerr != nil {
+		return lerr
+	}
+
+	priorityClass := template.Spec.PriorityClassName
+
 	switch priorityClass {
 	case "":
 		return errors.NewLintRuleError(
@@ -236,18 +189,13 @@ func objectSecurityContext(object storage.StoreObject) *errors.LintRuleError {
 		return nil
 	}
 
-	securityContext, err := object.GetPodSecurityContext()
-	if err != nil {
-		return errors.NewLintRuleError(
-			ID,
-			object.Identity(),
-			object.Unstructured.GetName(),
-			nil,
-			"GetPodSecurityContext failed: %v",
-			err,
-		)
+	template, lerr := podTemplate(object)
+	if lerr != nil {
+		return lerr
 	}
 
+	securityContext := template.Spec.SecurityContext
+
 	if securityContext == nil {
 		return errors.NewLintRuleError(
 			ID,
@@ -356,41 +304,13 @@ func objectHostNetworkPorts(object storage.StoreObject) *errors.LintRuleError {
 		return nil
 	}
 
-	hostNetworkUsed, err := object.IsHostNetwork()
-	if err != nil {
-		return errors.NewLintRuleError(
-			ID,
-			object.Identity(),
-			object.Unstructured.GetName(),
-			nil,
-			"IsHostNetwork failed: %v",
-			err,
-		)
+	template, lerr := podTemplate(object)
+	if lerr != nil {
+		return lerr
 	}
 
-	containers, err := object.GetContainers()
-	if err != nil {
-		return errors.NewLintRuleError(
-			ID,
-			object.Identity(),
-			object.Unstructured.GetName(),
-			nil,
-			"GetContainers failed: %v",
-			err,
-		)
-	}
-	initContainers, err := object.GetInitContainers()
-	if err != nil {
-		return errors.NewLintRuleError(
-			ID,
-			object.Identity(),
-			object.Unstructured.GetName(),
-			nil,
-			"GetInitContainers failed: %v",
-			err,
-		)
-	}
-	containers = append(containers, initContainers...)
+	hostNetworkUsed := template.Spec.HostNetwork
+	containers := slices.Concat(template.Spec.Containers, template.Spec.InitContainers)
 
 	for i := range containers {
 		for _, p := range containers[i].Ports {
@@ -419,47 +339,20 @@ func objectHostNetworkPorts(object storage.StoreObject) *errors.LintRuleError {
 }
 
 func objectDNSPolicy(object storage.StoreObject) *errors.LintRuleError {
-	kind := object.Unstructured.GetKind()
-	converter := runtime.DefaultUnstructuredConverter
-
-	var dnsPolicy string
-	var hostNetwork bool
-
-	switch kind {
-	case "Deployment":
-		deployment := new(appsv1.Deployment)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		dnsPolicy = string(deployment.Spec.Template.Spec.DNSPolicy)
-		hostNetwork = deployment.Spec.Template.Spec.HostNetwork
-	case "DaemonSet":
-		daemonset := new(appsv1.DaemonSet)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), daemonset)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		dnsPolicy = string(daemonset.Spec.Template.Spec.DNSPolicy)
-		hostNetwork = daemonset.Spec.Template.Spec.HostNetwork
-	case "StatefulSet":
-		statefulset := new(appsv1.StatefulSet)
-
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), statefulset)
-		if err != nil {
-			return newConvertError(object, err)
-		}
-
-		dnsPolicy = string(statefulset.Spec.Template.Spec.DNSPolicy)
-		hostNetwork = statefulset.Spec.Template.Spec.HostNetwork
+	switch object.Unstructured.GetKind() {
+	case "Deployment", "DaemonSet", "StatefulSet":
 	default:
 		return nil
 	}
 
+	template, lerr := podTemplate(object)
+	if lerr != nil {
+		return lerr
+	}
+
+	dnsPolicy := string(template.Spec.DNSPolicy)
+	hostNetwork := template.Spec.HostNetwork
+
 	if !hostNetwork {
 		return nil
 	}
@@ -0,0 +1,234 @@
+package k8sresources
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	// SelectorID is used for Service, ServiceMonitor and PodMonitor selectors and ports not resolved in the module
+	SelectorID = "selector"
+)
+
+// monitorSpec contains fields of ServiceMonitor and PodMonitor endpoints the selector rules resolve
+type monitorSpec struct {
+	Endpoints           []monitorEndpoint `json:"endpoints"`
+	PodMetricsEndpoints []monitorEndpoint `json:"podMetricsEndpoints"`
+}
+
+type monitorEndpoint struct {
+	Port       string              `json:"port"`
+	TargetPort *intstr.IntOrString `json:"targetPort"`
+}
+
+// servicePort contains fields of a Service port the selector rules resolve
+type servicePort struct {
+	name string
+	// targetPort is the name of the target port, it is empty for numeric target ports
+	targetPort string
+}
+
+// selectorRules resolves selectors of Services, ServiceMonitors and PodMonitors against objects of the module:
+// selectors must match something, named target ports must match container ports of selected pods,
+// ServiceMonitor endpoint ports must match ports of selected Services.
+// Objects matching externalSelectors `namespace/Kind/name` glob patterns select objects of other modules,
+// selectors matching nothing are not reported for them.
+func selectorRules(moduleName string, store *storage.UnstructuredObjectStore, externalSelectors []string) (result errors.LintRuleErrorsList) {
+	if store == nil {
+		return result
+	}
+
+	add := func(object storage.StoreObject, value any, template string, args ...any) {
+		result.Add(errors.NewLintRuleError(SelectorID, object.Identity(), moduleName, value, template, args...))
+	}
+	external := func(object storage.StoreObject) bool {
+		index := storage.GetResourceIndex(object)
+		return index.MatchesAny(externalSelectors)
+	}
+
+	for _, object := range store.ByKind("Service") {
+		selector, ports, err := serviceSpec(object)
+		if err != nil {
+			add(object, nil, "Cannot read Service spec: %s", err)
+			continue
+		}
+		// endpoints of services without selector are managed manually
+		if len(selector) == 0 {
+			continue
+		}
+
+		pods, err := store.SelectedPods(object)
+		if err != nil {
+			add(object, nil, "%s", err)
+			continue
+		}
+		if len(pods) == 0 {
+			if !external(object) {
+				add(object, selector, "Service selector matches no pods of the module")
+			}
+			continue
+		}
+
+		for _, port := range ports {
+			if port.targetPort != "" && !hasContainerPort(pods, port.targetPort) {
+				add(object, port.targetPort, "Service port %q targetPort %q matches no container port of selected pods", port.name, port.targetPort)
+			}
+		}
+	}
+
+	for _, object := range store.ByKind("ServiceMonitor") {
+		serviceMonitorRules(store, object, external(object), add)
+	}
+
+	for _, object := range store.ByKind("PodMonitor") {
+		podMonitorRules(store, object, external(object), add)
+	}
+
+	return result
+}
+
+// addFunc adds a finding for the object
+type addFunc func(object storage.StoreObject, value any, template string, args ...any)
+
+// serviceSpec reads the selector and ports of the Service
+func serviceSpec(object storage.StoreObject) (map[string]string, []servicePort, error) {
+	selector, _, err := unstructured.NestedStringMap(object.Unstructured.Object, "spec", "selector")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, _, err := unstructured.NestedSlice(object.Unstructured.Object, "spec", "ports")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ports := make([]servicePort, 0, len(items))
+	for i, item := range items {
+		port, ok := item.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf(".spec.ports[%d] accessor error: %v is of the type %T, expected map[string]interface{}", i, item, item)
+		}
+		name, _, _ := unstructured.NestedString(port, "name")
+		// numeric target ports are not resolved, so type errors are ignored
+		targetPort, _, _ := unstructured.NestedString(port, "targetPort")
+		ports = append(ports, servicePort{name: name, targetPort: targetPort})
+	}
+
+	return selector, ports, nil
+}
+
+func serviceMonitorRules(store *storage.UnstructuredObjectStore, object storage.StoreObject, external bool, add addFunc) {
+	spec, ok := parseMonitorSpec(object, add)
+	if !ok {
+		return
+	}
+
+	services, err := store.SelectedServices(object)
+	if err != nil {
+		add(object, nil, "%s", err)
+		return
+	}
+	if len(services) == 0 {
+		if !external {
+			add(object, nil, "ServiceMonitor selector matches no Services of the module")
+		}
+		return
+	}
+
+	var servicePorts []string
+	var pods []storage.StoreObject
+	for _, object := range services {
+		// spec and selector errors are reported for the Service
+		_, ports, err := serviceSpec(object)
+		if err != nil {
+			continue
+		}
+		for _, port := range ports {
+			servicePorts = append(servicePorts, port.name)
+		}
+
+		selected, _ := store.SelectedPods(object)
+		pods = append(pods, selected...)
+	}
+
+	for _, endpoint := range spec.Endpoints {
+		switch {
+		case endpoint.Port != "":
+			if !slices.Contains(servicePorts, endpoint.Port) {
+				add(object, endpoint.Port, "ServiceMonitor endpoint port %q matches no port of selected Services", endpoint.Port)
+			}
+		case endpoint.TargetPort != nil && endpoint.TargetPort.Type == intstr.String:
+			if !hasContainerPort(pods, endpoint.TargetPort.StrVal) {
+				add(object, endpoint.TargetPort.StrVal, "ServiceMonitor endpoint targetPort %q matches no container port of pods selected by Services", endpoint.TargetPort.StrVal)
+			}
+		}
+	}
+}
+
+func podMonitorRules(store *storage.UnstructuredObjectStore, object storage.StoreObject, external bool, add addFunc) {
+	spec, ok := parseMonitorSpec(object, add)
+	if !ok {
+		return
+	}
+
+	pods, err := store.SelectedPods(object)
+	if err != nil {
+		add(object, nil, "%s", err)
+		return
+	}
+	if len(pods) == 0 {
+		if !external {
+			add(object, nil, "PodMonitor selector matches no pods of the module")
+		}
+		return
+	}
+
+	for _, endpoint := range spec.PodMetricsEndpoints {
+		if endpoint.Port != "" && !hasContainerPort(pods, endpoint.Port) {
+			add(object, endpoint.Port, "PodMonitor endpoint port %q matches no container port of selected pods", endpoint.Port)
+		}
+	}
+}
+
+func parseMonitorSpec(object storage.StoreObject, add addFunc) (*monitorSpec, bool) {
+	content, ok := object.Unstructured.Object["spec"].(map[string]any)
+	if !ok {
+		add(object, nil, "Object has no spec")
+		return nil, false
+	}
+
+	spec := new(monitorSpec)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec); err != nil {
+		add(object, nil, "Cannot convert object: %s", err)
+		return nil, false
+	}
+
+	return spec, true
+}
+
+// hasContainerPort reports whether containers of the pods have a port with the name
+func hasContainerPort(pods []storage.StoreObject, name string) bool {
+	for _, pod := range pods {
+		template, err := pod.PodTemplate()
+		if err != nil || template == nil {
+			continue
+		}
+
+		for _, container := range slices.Concat(template.Spec.InitContainers, template.Spec.Containers) {
+			for _, port := range container.Ports {
+				if port.Name == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
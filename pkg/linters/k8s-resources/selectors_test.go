@@ -0,0 +1,117 @@
+package k8sresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deckhouse/dmt/internal/module/moduletest"
+)
+
+const selectorManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: d8-test
+spec:
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+        - name: web
+          ports:
+            - {name: http, containerPort: 8080}
+            - {name: metrics, containerPort: 9090}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: d8-test
+  labels: {app: web}
+spec:
+  selector: {app: web}
+  ports:
+    - {name: http, port: 80, targetPort: http}
+    - {name: https, port: 443, targetPort: https}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: d8-test
+spec:
+  selector: {app: api}
+  ports:
+    - {name: http, port: 80, targetPort: http}
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: web
+  namespace: d8-monitoring
+spec:
+  namespaceSelector:
+    matchNames: [d8-test]
+  selector:
+    matchLabels: {app: web}
+  endpoints:
+    - port: http
+    - port: metrics
+    - targetPort: metrics
+    - targetPort: debug
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PodMonitor
+metadata:
+  name: web
+  namespace: d8-monitoring
+spec:
+  namespaceSelector:
+    matchNames: [d8-test]
+  selector:
+    matchLabels: {app: web}
+  podMetricsEndpoints:
+    - port: metrics
+    - port: profiling
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: ingress
+  namespace: d8-monitoring
+spec:
+  namespaceSelector:
+    matchNames: [d8-ingress]
+  selector:
+    matchLabels: {app: ingress}
+  endpoints:
+    - port: metrics
+`
+
+func TestSelectorRules(t *testing.T) {
+	store := moduletest.ObjectStore(t, selectorManifests)
+
+	result := selectorRules("test", store, nil)
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, `Service port "https" targetPort "https" matches no container port of selected pods`)
+	assert.NotContains(t, text, `targetPort "http" matches`)
+	assert.Contains(t, text, "kind = Service ; name = api ; namespace = d8-test")
+	assert.Contains(t, text, "Service selector matches no pods of the module")
+	assert.Contains(t, text, `ServiceMonitor endpoint port "metrics" matches no port of selected Services`)
+	assert.NotContains(t, text, `endpoint port "http"`)
+	assert.Contains(t, text, `ServiceMonitor endpoint targetPort "debug" matches no container port`)
+	assert.NotContains(t, text, `targetPort "metrics"`)
+	assert.Contains(t, text, `PodMonitor endpoint port "profiling" matches no container port of selected pods`)
+	assert.NotContains(t, text, `PodMonitor endpoint port "metrics"`)
+	assert.Contains(t, text, "kind = ServiceMonitor ; name = ingress ; namespace = d8-monitoring")
+	assert.Contains(t, text, "ServiceMonitor selector matches no Services of the module")
+
+	result = selectorRules("test", store, []string{"d8-test/Service/api", "d8-monitoring/ServiceMonitor/ingress"})
+	text = result.ConvertToError().Error()
+	assert.NotContains(t, text, "Service selector matches no pods of the module")
+	assert.NotContains(t, text, "ServiceMonitor selector matches no Services of the module")
+	assert.Contains(t, text, `Service port "https" targetPort "https" matches no container port of selected pods`)
+}
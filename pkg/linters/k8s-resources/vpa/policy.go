@@ -0,0 +1,89 @@
+package vpa
+
+import (
+	"slices"
+
+	"github.com/flant/addon-operator/sdk"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+// ManagedContainerPolicy returns the resource policy of the workload container if a VPA of the module manages
+// its resources: the VPA targets the workload, its update mode is not Off and the policy of the container
+// (or the `*` policy) does not turn scaling off. Containers without a policy get the default one.
+func ManagedContainerPolicy(md *module.Module, workload storage.StoreObject, container string) (*ContainerResourcePolicy, bool) {
+	for _, object := range md.GetObjectStore().ByTarget(storage.GetResourceIndex(workload)) {
+		if object.Unstructured.GetKind() != "VerticalPodAutoscaler" {
+			continue
+		}
+
+		// decoding errors are reported by ControllerMustHaveVPA
+		v, err := decodeVPA(object)
+		if err != nil || v.updateMode() == UpdateModeOff {
+			continue
+		}
+
+		policy := v.containerPolicy(container)
+		if policy.Mode != nil && *policy.Mode == ContainerScalingModeOff {
+			continue
+		}
+
+		return policy, true
+	}
+
+	return nil, false
+}
+
+// Controls reports whether VPA computes recommendations for the resource, CPU and memory by default
+func (p *ContainerResourcePolicy) Controls(resource v1.ResourceName) bool {
+	if p.ControlledResources == nil {
+		return resource == v1.ResourceCPU || resource == v1.ResourceMemory
+	}
+
+	return slices.Contains(*p.ControlledResources, resource)
+}
+
+// ControlsLimits reports whether VPA scales limits proportionally to requests
+func (p *ContainerResourcePolicy) ControlsLimits() bool {
+	return p.ControlledValues == nil || *p.ControlledValues == ContainerControlledValuesRequestsAndLimits
+}
+
+func decodeVPA(object storage.StoreObject) (*VerticalPodAutoscaler, error) {
+	v := &VerticalPodAutoscaler{}
+	if err := sdk.FromUnstructured(&object.Unstructured, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// updateMode returns the update mode of the VPA, Auto if it is not set
+func (v *VerticalPodAutoscaler) updateMode() UpdateMode {
+	if v.Spec.UpdatePolicy == nil || v.Spec.UpdatePolicy.UpdateMode == nil {
+		return UpdateModeAuto
+	}
+
+	return *v.Spec.UpdatePolicy.UpdateMode
+}
+
+// containerPolicy returns the policy of the container, the `*` policy or an empty policy
+func (v *VerticalPodAutoscaler) containerPolicy(container string) *ContainerResourcePolicy {
+	result := &ContainerResourcePolicy{ContainerName: container}
+	if v.Spec.ResourcePolicy == nil {
+		return result
+	}
+
+	for i := range v.Spec.ResourcePolicy.ContainerPolicies {
+		policy := &v.Spec.ResourcePolicy.ContainerPolicies[i]
+		switch policy.ContainerName {
+		case container:
+			return policy
+		case DefaultContainerResourcePolicy:
+			result = policy
+		}
+	}
+
+	return result
+}
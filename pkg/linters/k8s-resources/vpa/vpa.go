@@ -20,10 +20,7 @@ import (
 	"fmt"
 	"slices"
 
-	"github.com/flant/addon-operator/sdk"
-	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/internal/set"
@@ -46,11 +43,8 @@ func ControllerMustHaveVPA(md *module.Module) (result errors.LintRuleErrorsList)
 	vpaTargets, vpaTolerationGroups, vpaContainerNamesMap, vpaUpdateModes, errs := parseTargetsAndTolerationGroups(md)
 	result.Merge(errs)
 
-	for index, object := range md.GetObjectStore().Storage {
-		// Skip non-pod controllers
-		if !IsPodController(object.Unstructured.GetKind()) {
-			continue
-		}
+	for _, object := range md.GetObjectStore().ByKind(podControllerKinds...) {
+		index := storage.GetResourceIndex(object)
 
 		ok, errs := ensureVPAIsPresent(md, vpaTargets, index, object)
 		result.Merge(errs)
@@ -75,9 +69,7 @@ func ControllerMustHaveVPA(md *module.Module) (result errors.LintRuleErrorsList)
 	return result
 }
 
-func IsPodController(kind string) bool {
-	return kind == "Deployment" || kind == "DaemonSet" || kind == "StatefulSet"
-}
+var podControllerKinds = []string{"Deployment", "DaemonSet", "StatefulSet"}
 
 // parseTargetsAndTolerationGroups resolves target resource indexes
 func parseTargetsAndTolerationGroups(md *module.Module) (
@@ -91,13 +83,7 @@ func parseTargetsAndTolerationGroups(md *module.Module) (
 	vpaContainerNamesMap = make(map[storage.ResourceIndex]set.Set)
 	vpaUpdateModes = make(map[storage.ResourceIndex]UpdateMode)
 
-	for _, object := range md.GetObjectStore().Storage {
-		kind := object.Unstructured.GetKind()
-
-		if kind != "VerticalPodAutoscaler" {
-			continue
-		}
-
+	for _, object := range md.GetObjectStore().ByKind("VerticalPodAutoscaler") {
 		result.Merge(fillVPAMaps(md, vpaTargets, vpaTolerationGroups, vpaContainerNamesMap, vpaUpdateModes, object))
 	}
 
@@ -141,9 +127,7 @@ func parseVPAResourcePolicyContainers(md *module.Module, vpaObject storage.Store
 	result := errors.LintRuleErrorsList{}
 	containers := set.New()
 
-	v := &VerticalPodAutoscaler{}
-	err := sdk.FromUnstructured(&vpaObject.Unstructured, v)
-
+	v, err := decodeVPA(vpaObject)
 	if err != nil {
 		result.Add(errors.NewLintRuleError(
 			ID, vpaObject.Identity(), md.GetName(), false, "Cannot unmarshal VPA object: %v", err,
@@ -151,7 +135,7 @@ func parseVPAResourcePolicyContainers(md *module.Module, vpaObject storage.Store
 		return "", containers, false, result
 	}
 
-	updateMode := *v.Spec.UpdatePolicy.UpdateMode
+	updateMode := v.updateMode()
 	if updateMode == UpdateModeOff {
 		return updateMode, containers, true, result
 	}
@@ -260,7 +244,7 @@ func ensureVPAContainersMatchControllerContainers(
 		return false, result
 	}
 
-	containers, err := object.GetContainers()
+	template, err := object.PodTemplate()
 	if err != nil {
 		result.Add(errors.NewLintRuleError(
 			ID,
@@ -274,8 +258,10 @@ func ensureVPAContainersMatchControllerContainers(
 	}
 
 	containerNames := set.New()
-	for i := range containers {
-		containerNames.Add(containers[i].Name)
+	if template != nil {
+		for i := range template.Spec.Containers {
+			containerNames.Add(template.Spec.Containers[i].Name)
+		}
 	}
 
 	for k := range containerNames {
@@ -380,34 +366,16 @@ func ensureVPAIsPresent(
 }
 
 func getTolerationsList(object storage.StoreObject) ([]v1.Toleration, error) {
-	var tolerations []v1.Toleration
-	converter := runtime.DefaultUnstructuredConverter
-
 	switch object.Unstructured.GetKind() {
-	case "Deployment":
-		deployment := new(appsv1.Deployment)
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), deployment)
-		if err != nil {
-			return nil, err
-		}
-		tolerations = deployment.Spec.Template.Spec.Tolerations
-
-	case "DaemonSet":
-		daemonset := new(appsv1.DaemonSet)
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), daemonset)
-		if err != nil {
-			return nil, err
-		}
-		tolerations = daemonset.Spec.Template.Spec.Tolerations
+	case "Deployment", "DaemonSet", "StatefulSet":
+	default:
+		return nil, nil
+	}
 
-	case "StatefulSet":
-		statefulset := new(appsv1.StatefulSet)
-		err := converter.FromUnstructured(object.Unstructured.UnstructuredContent(), statefulset)
-		if err != nil {
-			return nil, err
-		}
-		tolerations = statefulset.Spec.Template.Spec.Tolerations
+	template, err := object.PodTemplate()
+	if err != nil {
+		return nil, err
 	}
 
-	return tolerations, nil
+	return template.Spec.Tolerations, nil
 }
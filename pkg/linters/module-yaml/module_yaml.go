@@ -0,0 +1,49 @@
+package moduleyaml
+
+import (
+	"slices"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "module-yaml"
+)
+
+// ModuleYaml linter
+type ModuleYaml struct {
+	name, desc string
+	cfg        *config.ModuleYamlSettings
+}
+
+func New(cfg *config.ModuleYamlSettings) *ModuleYaml {
+	return &ModuleYaml{
+		name: "module-yaml",
+		desc: "Lint module.yaml fields and its consistency with Chart.yaml",
+		cfg:  cfg,
+	}
+}
+
+func (o *ModuleYaml) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+	if m == nil || m.GetDefinition() == nil {
+		return result, err
+	}
+
+	if slices.Contains(o.cfg.SkipModuleChecks, m.GetName()) {
+		return result, err
+	}
+
+	result.Merge(applyDefinitionRules(m))
+
+	return result, nil
+}
+
+func (o *ModuleYaml) Name() string {
+	return o.name
+}
+
+func (o *ModuleYaml) Desc() string {
+	return o.desc
+}
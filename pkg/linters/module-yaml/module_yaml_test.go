@@ -0,0 +1,122 @@
+package moduleyaml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+)
+
+func newModule(t *testing.T, files map[string]string) *module.Module {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["openapi/values.yaml"] = "type: object\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	m, err := module.NewModule(dir, nil)
+	require.NoError(t, err)
+
+	return m
+}
+
+func TestModuleYamlValid(t *testing.T) {
+	m := newModule(t, map[string]string{
+		module.ModuleYamlFilename: `
+name: test-module
+namespace: d8-test-module
+weight: 910
+stage: Preview
+tags: ["observability", "logs"]
+requirements:
+  kubernetes: ">= 1.27"
+  deckhouse: ">= 1.60"
+  modules:
+    prometheus: ">= 0.0.0"
+descriptions:
+  en: Test module
+  ru: Тестовый модуль
+`,
+	})
+
+	assert.Equal(t, "test-module", m.GetName())
+	assert.Equal(t, "d8-test-module", m.GetNamespace())
+
+	result, err := New(&config.ModuleYamlSettings{}).Run(m)
+	require.NoError(t, err)
+	assert.Nil(t, result.ConvertToError())
+}
+
+func TestModuleYamlInvalid(t *testing.T) {
+	m := newModule(t, map[string]string{
+		module.ModuleYamlFilename: `
+name: test-module
+namespace: d8-test-module
+weight: 1000
+stage: Beta
+tags: ["Logs", "logs", "logs"]
+requirements:
+  kubernetes: "not a version"
+  modules:
+    test-module: ">= 0.0.0"
+descriptions:
+  ru: Тестовый модуль
+`,
+	})
+
+	result, err := New(&config.ModuleYamlSettings{}).Run(m)
+	require.NoError(t, err)
+
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "Field \"weight\" must be in range [0, 999]")
+	assert.Contains(t, text, "Field \"stage\" must be one of")
+	assert.Contains(t, text, "Tag must consist of lowercase alphanumeric characters")
+	assert.Contains(t, text, "Duplicate tag")
+	assert.Contains(t, text, "Field \"requirements.kubernetes\" is not a valid version constraint")
+	assert.Contains(t, text, "Module must not require itself")
+	assert.Contains(t, text, "Field \"descriptions.en\" is required")
+	assert.NotContains(t, text, "Field \"name\"")
+	assert.NotContains(t, text, "Field \"namespace\"")
+}
+
+func TestModuleYamlConsistency(t *testing.T) {
+	m := newModule(t, map[string]string{
+		module.ModuleYamlFilename:  "name: test-module\nnamespace: d8-test-module\n",
+		module.ChartConfigFilename: "apiVersion: v2\nname: legacy-module\nversion: 0.0.1\n",
+		module.NamespaceFilename:   "d8-legacy-module\n",
+	})
+
+	result, err := New(&config.ModuleYamlSettings{}).Run(m)
+	require.NoError(t, err)
+
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "Field \"name\" \"test-module\" does not match the chart name in \"Chart.yaml\"")
+	assert.Contains(t, text, "Field \"namespace\" \"d8-test-module\" does not match the namespace in \".namespace\"")
+
+	result, err = New(&config.ModuleYamlSettings{SkipModuleChecks: []string{m.GetName()}}).Run(m)
+	require.NoError(t, err)
+	assert.Nil(t, result.ConvertToError())
+}
+
+func TestModuleYamlAbsent(t *testing.T) {
+	m := newModule(t, map[string]string{
+		module.ChartConfigFilename: "apiVersion: v2\nname: legacy-module\nversion: 0.0.1\n",
+		module.NamespaceFilename:   "d8-legacy-module\n",
+	})
+
+	assert.Nil(t, m.GetDefinition())
+	assert.Equal(t, "legacy-module", m.GetName())
+
+	result, err := New(&config.ModuleYamlSettings{}).Run(m)
+	require.NoError(t, err)
+	assert.Nil(t, result.ConvertToError())
+}
@@ -0,0 +1,185 @@
+package moduleyaml
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	maxWeight = 999
+)
+
+var (
+	nameRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	tagRe  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	allowedStages = []string{"Sandbox", "Experimental", "Preview", "General Availability", "Deprecated"}
+)
+
+func applyDefinitionRules(m *module.Module) (result errors.LintRuleErrorsList) {
+	def := m.GetDefinition()
+	objectID := fmt.Sprintf("module = %s ; file = %s", m.GetName(), module.ModuleYamlFilename)
+
+	result.Add(nameRule(m, def, objectID))
+	result.Add(namespaceRule(m, def, objectID))
+	result.Add(weightRule(m, def, objectID))
+	result.Add(stageRule(m, def, objectID))
+	result.Merge(tagsRule(m, def, objectID))
+	result.Merge(requirementsRule(m, def, objectID))
+	result.Add(descriptionsRule(m, def, objectID))
+
+	return result
+}
+
+func nameRule(m *module.Module, def *module.Definition, objectID string) *errors.LintRuleError {
+	if def.Name == "" {
+		return errors.NewLintRuleError(ID, objectID, m.GetName(), nil, "Field \"name\" is required")
+	}
+
+	if !nameRe.MatchString(def.Name) {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), def.Name,
+			"Field \"name\" must consist of lowercase alphanumeric characters separated by '-'",
+		)
+	}
+
+	// Chart.yaml is generated from module.yaml if it is absent
+	if _, err := os.Stat(filepath.Join(m.GetPath(), module.ChartConfigFilename)); err != nil {
+		return nil
+	}
+
+	if metadata := m.GetMetadata(); metadata != nil && metadata.Name != def.Name {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), metadata.Name,
+			"Field \"name\" %q does not match the chart name in %q", def.Name, module.ChartConfigFilename,
+		)
+	}
+
+	return nil
+}
+
+func namespaceRule(m *module.Module, def *module.Definition, objectID string) *errors.LintRuleError {
+	if def.Namespace == "" {
+		return nil
+	}
+
+	if msgs := validation.IsDNS1123Label(def.Namespace); len(msgs) > 0 {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), def.Namespace,
+			"Field \"namespace\" is not a valid namespace name: %s", strings.Join(msgs, "; "),
+		)
+	}
+
+	content, err := os.ReadFile(filepath.Join(m.GetPath(), module.NamespaceFilename))
+	if err != nil {
+		return nil
+	}
+
+	if namespace := strings.TrimRight(string(content), " \t\n"); namespace != def.Namespace {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), namespace,
+			"Field \"namespace\" %q does not match the namespace in %q", def.Namespace, module.NamespaceFilename,
+		)
+	}
+
+	return nil
+}
+
+func weightRule(m *module.Module, def *module.Definition, objectID string) *errors.LintRuleError {
+	if def.Weight < 0 || def.Weight > maxWeight {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), def.Weight,
+			"Field \"weight\" must be in range [0, %d]", maxWeight,
+		)
+	}
+
+	return nil
+}
+
+func stageRule(m *module.Module, def *module.Definition, objectID string) *errors.LintRuleError {
+	if def.Stage == "" || slices.Contains(allowedStages, def.Stage) {
+		return nil
+	}
+
+	return errors.NewLintRuleError(
+		ID, objectID, m.GetName(), def.Stage,
+		"Field \"stage\" must be one of: %s", strings.Join(allowedStages, ", "),
+	)
+}
+
+func tagsRule(m *module.Module, def *module.Definition, objectID string) (result errors.LintRuleErrorsList) {
+	seen := make(map[string]struct{}, len(def.Tags))
+	for _, tag := range def.Tags {
+		if !tagRe.MatchString(tag) {
+			result.Add(errors.NewLintRuleError(
+				ID, objectID, m.GetName(), tag,
+				"Tag must consist of lowercase alphanumeric characters separated by '-'",
+			))
+		}
+
+		if _, ok := seen[tag]; ok {
+			result.Add(errors.NewLintRuleError(
+				ID, objectID, m.GetName(), tag,
+				"Duplicate tag",
+			))
+		}
+		seen[tag] = struct{}{}
+	}
+
+	return result
+}
+
+func requirementsRule(m *module.Module, def *module.Definition, objectID string) (result errors.LintRuleErrorsList) {
+	result.Add(constraintRule(m, objectID, "requirements.kubernetes", def.Requirements.Kubernetes))
+	result.Add(constraintRule(m, objectID, "requirements.deckhouse", def.Requirements.Deckhouse))
+
+	for name, constraint := range def.Requirements.Modules {
+		if name == def.Name {
+			result.Add(errors.NewLintRuleError(
+				ID, objectID, m.GetName(), name,
+				"Module must not require itself",
+			))
+			continue
+		}
+
+		result.Add(constraintRule(m, objectID, "requirements.modules."+name, constraint))
+	}
+
+	return result
+}
+
+func constraintRule(m *module.Module, objectID, field, constraint string) *errors.LintRuleError {
+	if constraint == "" {
+		return nil
+	}
+
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), constraint,
+			"Field %q is not a valid version constraint: %v", field, err,
+		)
+	}
+
+	return nil
+}
+
+func descriptionsRule(m *module.Module, def *module.Definition, objectID string) *errors.LintRuleError {
+	if def.Descriptions.Ru != "" && def.Descriptions.En == "" {
+		return errors.NewLintRuleError(
+			ID, objectID, m.GetName(), nil,
+			"Field \"descriptions.en\" is required if \"descriptions.ru\" is set",
+		)
+	}
+
+	return nil
+}
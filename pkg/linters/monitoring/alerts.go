@@ -0,0 +1,259 @@
+package monitoring
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	// AlertsID is used for alerting and recording rules not following Deckhouse conventions
+	AlertsID = "alerts"
+	// UnknownAnnotationID is used for plk annotations unknown to dmt, it is always a warning,
+	// since the alert manager may support annotations added after the dmt release
+	UnknownAnnotationID = "alerts-unknown-annotation"
+
+	// DefaultMinRateRange is the shortest range of rate functions, it covers at least two scrapes with the default 30s interval
+	DefaultMinRateRange = model.Duration(time.Minute)
+
+	// maxSeverityLevel is the least severe level, 1 is the most severe one
+	maxSeverityLevel = 9
+)
+
+// MinRateRange is the shortest range of rate functions in rule expressions
+var MinRateRange = DefaultMinRateRange
+
+var (
+	alertNameRe = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	// groupReferenceRe matches `GroupAlertName,label=value,label=~regex` values of plk group annotations
+	groupReferenceRe = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*(,[a-zA-Z_][a-zA-Z0-9_]*=~?[^,]*)*$`)
+	labelNamesRe     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(,[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+)
+
+// rateFunctions must be called over a range covering several scrapes
+var rateFunctions = []string{"rate", "irate", "increase"}
+
+// plkAnnotations validates values of annotations read by the Deckhouse alert manager (Polk)
+var plkAnnotations = map[string]func(value string) error{
+	"plk_protocol_version":         oneOf("1"),
+	"plk_markup_format":            oneOf("default", "markdown"),
+	"plk_pending_until_firing_for": duration,
+	"plk_labels_as_annotations":    matches(labelNamesRe, "comma-separated label names"),
+	"plk_ignore_labels":            matches(labelNamesRe, "comma-separated label names"),
+	"plk_incident_initial_status":  notEmpty,
+}
+
+// plkAnnotationPrefixes validates values of plk annotations with a `__<name>` suffix
+var plkAnnotationPrefixes = map[string]func(value string) error{
+	"plk_create_group_if_not_exists__": matches(groupReferenceRe, "`GroupAlertName,label=value,...`"),
+	"plk_grouped_by__":                 matches(groupReferenceRe, "`GroupAlertName,label=value,...`"),
+	"plk_cause_of__":                   matches(groupReferenceRe, "`AlertName,label=value,...`"),
+	"plk_group_for__":                  duration,
+}
+
+// alertLocation is where an alert is defined first
+type alertLocation struct {
+	object string
+	group  string
+}
+
+// AlertsCheck checks rules of PrometheusRule objects of the module against Deckhouse conventions:
+// alerts must have a numeric `severity_level` label, `summary` and `description` annotations, well-formed
+// `plk_*` annotations and CamelCase names unique across the module, alerts with the same name in one group
+// are variants of the alert, e.g. with different severity. Rate functions must be called over ranges
+// not shorter than MinRateRange. Objects with invalid rules are checked as far as they can be decoded.
+func AlertsCheck(moduleName string, objects []storage.StoreObject) (result errors.LintRuleErrorsList) {
+	alerts := make(map[string]alertLocation)
+
+	for _, object := range objects {
+		groups, _ := parseRuleGroups(object)
+		if groups == nil {
+			continue
+		}
+
+		for _, group := range groups.Groups {
+			for _, rule := range group.Rules {
+				result.Merge(ruleRangeErrors(moduleName, object, group.Name, rule))
+
+				if rule.Alert == "" {
+					continue
+				}
+				result.Merge(alertErrors(moduleName, object, group.Name, rule))
+
+				location := alertLocation{object: object.Identity(), group: group.Name}
+				first, ok := alerts[rule.Alert]
+				if !ok {
+					alerts[rule.Alert] = location
+					continue
+				}
+				if first != location {
+					result.Add(errors.NewLintRuleError(
+						AlertsID,
+						object.Identity(),
+						moduleName,
+						group.Name+"/"+rule.Alert,
+						"Alert %q in group %q is already defined in group %q of %s", rule.Alert, group.Name, first.group, first.object,
+					))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func alertErrors(moduleName string, object storage.StoreObject, group string, rule ruleSpec) (result errors.LintRuleErrorsList) {
+	add := func(template string, args ...any) {
+		result.Add(errors.NewLintRuleError(
+			AlertsID,
+			object.Identity(),
+			moduleName,
+			group+"/"+rule.Alert,
+			"Alert %q in group %q: %s", rule.Alert, group, fmt.Sprintf(template, args...),
+		))
+	}
+
+	if !alertNameRe.MatchString(rule.Alert) {
+		add("name must be CamelCase")
+	}
+
+	if level, ok := rule.Labels["severity_level"]; !ok {
+		add("`severity_level` label is required")
+	} else if n, err := strconv.Atoi(level); !strings.Contains(level, "{{") && (err != nil || n < 1 || n > maxSeverityLevel) {
+		add("`severity_level` label must be a number from 1 to %d, got %q", maxSeverityLevel, level)
+	}
+
+	for _, name := range []string{"summary", "description"} {
+		if strings.TrimSpace(rule.Annotations[name]) == "" {
+			add("`%s` annotation is required", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(rule.Annotations)) {
+		if !strings.HasPrefix(name, "plk_") {
+			continue
+		}
+
+		validate, known := plkAnnotationValidator(name)
+		if !known {
+			result.Add(errors.NewLintRuleError(
+				UnknownAnnotationID,
+				object.Identity(),
+				moduleName,
+				group+"/"+rule.Alert,
+				"Alert %q in group %q: annotation %q is not a known plk annotation", rule.Alert, group, name,
+			))
+			continue
+		}
+		if err := validate(rule.Annotations[name]); err != nil {
+			add("annotation %q: %s", name, err)
+		}
+	}
+
+	return result
+}
+
+// plkAnnotationValidator returns the validator of the plk annotation, it returns false for unknown annotations
+func plkAnnotationValidator(name string) (func(value string) error, bool) {
+	if validate, ok := plkAnnotations[name]; ok {
+		return validate, true
+	}
+
+	for prefix, validate := range plkAnnotationPrefixes {
+		if suffix, ok := strings.CutPrefix(name, prefix); ok {
+			if suffix == "" {
+				return func(string) error { return fmt.Errorf("name must have a suffix after %q", prefix) }, true
+			}
+			return validate, true
+		}
+	}
+
+	return nil, false
+}
+
+// ruleRangeErrors reports rate functions called over ranges shorter than MinRateRange.
+// Expressions which can't be parsed are reported by PrometheusRuleCheck.
+func ruleRangeErrors(moduleName string, object storage.StoreObject, group string, rule ruleSpec) (result errors.LintRuleErrorsList) {
+	expr, err := parser.ParseExpr(rule.Expr)
+	if err != nil {
+		return result
+	}
+
+	name, kind := rule.Alert, "Alert"
+	if name == "" {
+		name, kind = rule.Record, "Recording rule"
+	}
+
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		call, ok := node.(*parser.Call)
+		if !ok || !slices.Contains(rateFunctions, call.Func.Name) || len(call.Args) == 0 {
+			return nil
+		}
+
+		var rng time.Duration
+		switch arg := call.Args[0].(type) {
+		case *parser.MatrixSelector:
+			rng = arg.Range
+		case *parser.SubqueryExpr:
+			rng = arg.Range
+		default:
+			return nil
+		}
+
+		if rng < time.Duration(MinRateRange) {
+			result.Add(errors.NewLintRuleError(
+				AlertsID,
+				object.Identity(),
+				moduleName,
+				group+"/"+name,
+				"%s %q in group %q: %s() range %s is shorter than %s", kind, name, group, call.Func.Name, model.Duration(rng), MinRateRange,
+			))
+		}
+
+		return nil
+	})
+
+	return result
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("must be one of %s, got %q", strings.Join(values, ", "), value)
+		}
+		return nil
+	}
+}
+
+func matches(re *regexp.Regexp, format string) func(string) error {
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("must have the %s format, got %q", format, value)
+		}
+		return nil
+	}
+}
+
+func notEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+func duration(value string) error {
+	if _, err := model.ParseDuration(value); err != nil {
+		return fmt.Errorf("must be a duration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+const alertsRule = `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: alerts
+spec:
+  groups:
+    - name: test.alerts
+      rules:
+        - alert: TestDown
+          expr: up{job="test"} == 0
+          labels:
+            severity_level: "4"
+          annotations:
+            plk_protocol_version: "1"
+            plk_markup_format: markdown
+            plk_create_group_if_not_exists__test: "TestGroup,prometheus=deckhouse,kubernetes=~kubernetes"
+            summary: Test is down.
+            description: Test is down.
+        - alert: TestDown
+          expr: up{job="test"} == 0
+          for: 10m
+          labels:
+            severity_level: "3"
+          annotations:
+            summary: Test is down.
+            description: Test is down.
+        - alert: test_errors
+          expr: rate(errors_total[30s]) > 0
+          labels:
+            severity_level: critical
+          annotations:
+            plk_markup_format: html
+            plk_grouped_by__test: "test group"
+            plk_unknown: "true"
+            summary: Test errors.
+    - name: test.other
+      rules:
+        - alert: TestDown
+          expr: up{job="test"} == 0
+          labels:
+            severity_level: "4"
+          annotations:
+            summary: Test is down.
+            description: Test is down.
+        - record: test:errors:rate
+          expr: sum(increase(errors_total[5m:15s])) + sum(irate(errors_total[15s]))
+`
+
+func TestAlertsCheck(t *testing.T) {
+	result := AlertsCheck("test", []storage.StoreObject{parseObject(t, alertsRule)})
+	text := result.ConvertToError().Error()
+
+	assert.NotContains(t, text, `Alert "TestDown" in group "test.alerts"`)
+	assert.Contains(t, text, `Alert "TestDown" in group "test.other" is already defined in group "test.alerts"`)
+
+	assert.Contains(t, text, `Alert "test_errors" in group "test.alerts": name must be CamelCase`)
+	assert.Contains(t, text, "`severity_level` label must be a number from 1 to 9, got \"critical\"")
+	assert.Contains(t, text, "`description` annotation is required")
+	assert.Contains(t, text, `annotation "plk_markup_format": must be one of default, markdown, got "html"`)
+	assert.Contains(t, text, `annotation "plk_grouped_by__test": must have the`)
+	assert.Contains(t, text, `annotation "plk_unknown" is not a known plk annotation`)
+	assert.Contains(t, text, `rate() range 30s is shorter than 1m`)
+
+	assert.Contains(t, text, `Recording rule "test:errors:rate" in group "test.other": irate() range 15s is shorter than 1m`)
+	assert.NotContains(t, text, "increase()")
+	assert.Contains(t, text, "[#"+UnknownAnnotationID+"]")
+}
@@ -1,6 +1,8 @@
 package monitoring
 
 import (
+	"github.com/prometheus/common/model"
+
 	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/pkg/config"
 	"github.com/deckhouse/dmt/pkg/errors"
@@ -18,6 +20,11 @@ var Cfg *config.MonitoringSettings
 
 func New(cfg *config.MonitoringSettings) *Monitoring {
 	Cfg = cfg
+	MinRateRange = DefaultMinRateRange
+	if cfg.MinRateRange != "" {
+		// the config validates the duration
+		MinRateRange, _ = model.ParseDuration(cfg.MinRateRange)
+	}
 
 	return &Monitoring{
 		name: "monitoring",
@@ -33,10 +40,10 @@ func (*Monitoring) Run(m *module.Module) (result errors.LintRuleErrorsList, err
 
 	result.Add(MonitoringModuleRule(m.GetName(), m.GetPath(), m.GetNamespace()))
 
-	// TODO: compile code instead of external binary - promtool
-	for _, object := range m.GetStorage() {
-		result.Add(PromtoolRuleCheck(m, object))
+	for _, object := range m.GetObjectStore().ByKind("PrometheusRule") {
+		result.Merge(PrometheusRuleCheck(m.GetName(), object))
 	}
+	result.Merge(AlertsCheck(m.GetName(), m.GetObjectStore().ByKind("PrometheusRule")))
 
 	return result, nil
 }
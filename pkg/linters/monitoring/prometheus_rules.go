@@ -17,144 +17,278 @@ limitations under the License.
 package monitoring
 
 import (
-	"os"
-	"os/exec"
-	"sync"
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
 
-	"sigs.k8s.io/yaml"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
 
-	"github.com/deckhouse/dmt/internal/module"
 	"github.com/deckhouse/dmt/internal/storage"
 	"github.com/deckhouse/dmt/pkg/errors"
 )
 
-type checkResult struct {
-	success bool
-	errMsg  string
+// ruleGroups is the PrometheusRule spec, it has the format of Prometheus rule files
+type ruleGroups struct {
+	Groups []ruleGroup `yaml:"groups"`
 }
 
-type rulesCacheStruct struct {
-	cache map[string]checkResult
-	mu    sync.RWMutex
+type ruleGroup struct {
+	Name     string     `yaml:"name"`
+	Interval string     `yaml:"interval,omitempty"`
+	Limit    int        `yaml:"limit,omitempty"`
+	Rules    []ruleSpec `yaml:"rules"`
 }
 
-const promtoolPath = "/deckhouse/bin/promtool"
+type ruleSpec struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           string            `yaml:"for,omitempty"`
+	KeepFiringFor string            `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
 
-var rulesCache = rulesCacheStruct{
-	cache: make(map[string]checkResult),
-	mu:    sync.RWMutex{},
+// name returns the alert name or the recorded metric name
+func (r *ruleSpec) name() string {
+	if r.Alert != "" {
+		return r.Alert
+	}
+	return r.Record
 }
 
-func (*rulesCacheStruct) Put(hash string, value checkResult) {
-	rulesCache.mu.Lock()
-	defer rulesCache.mu.Unlock()
+// ruleError is an invalid rule of the group, the rule number is used for rules without a name
+type ruleError struct {
+	group  string
+	number int
+	name   string
+	err    error
+}
 
-	rulesCache.cache[hash] = value
+func (e *ruleError) Error() string {
+	return fmt.Sprintf("group %q, rule %d, %q: %s", e.group, e.number, e.name, e.err)
 }
 
-func (*rulesCacheStruct) Get(hash string) (checkResult, bool) {
-	rulesCache.mu.RLock()
-	defer rulesCache.mu.RUnlock()
+// templateDefs are variables Prometheus defines for alert label and annotation templates
+const templateDefs = "{{$labels := .Labels}}{{$externalLabels := .ExternalLabels}}{{$externalURL := .ExternalURL}}{{$value := .Value}}"
 
-	res, ok := rulesCache.cache[hash]
-	return res, ok
+// templateFuncs contains functions of Prometheus alert templates, templates are only parsed, so they are not implemented
+var templateFuncs = template.FuncMap{}
+
+func init() {
+	for _, name := range []string{
+		"query", "first", "label", "value", "strvalue", "args", "reReplaceAll", "safeHtml", "match", "title", "toUpper",
+		"toLower", "graphLink", "tableLink", "sortByLabel", "stripPort", "stripDomain", "humanize", "humanize1024",
+		"humanizeDuration", "humanizePercentage", "humanizeTimestamp", "toTime", "pathPrefix", "externalURL", "parseDuration",
+	} {
+		templateFuncs[name] = func(...any) any { return nil }
+	}
 }
 
-func marshalChartYaml(object storage.StoreObject) ([]byte, error) {
-	marshal, err := yaml.Marshal(object.Unstructured.Object["spec"])
-	if err != nil {
-		return nil, err
+// PrometheusRuleCheck validates groups of the PrometheusRule the way Prometheus loads rule files:
+// group names, PromQL expressions, `for` durations, label and annotation templates.
+// Rules of the object with the same name and labels are reported as duplicates.
+func PrometheusRuleCheck(moduleName string, object storage.StoreObject) (result errors.LintRuleErrorsList) {
+	if object.Unstructured.GetKind() != "PrometheusRule" {
+		return result
+	}
+
+	groups, errs := parseRuleGroups(object)
+	for _, err := range errs {
+		result.Add(ruleLintError(moduleName, object, err))
 	}
-	return marshal, nil
+	if groups != nil {
+		result.Merge(duplicateRules(moduleName, object, groups))
+	}
+
+	return result
 }
 
-func writeTempRuleFileFromObject(m *module.Module, marshalledYaml []byte) (path string, err error) {
-	renderedFile, err := os.CreateTemp("", m.GetName()+".*.yml")
+// parseRuleGroups parses and validates groups of the PrometheusRule spec, groups are nil if the spec can't be decoded
+func parseRuleGroups(object storage.StoreObject) (*ruleGroups, []error) {
+	content, err := yaml.Marshal(object.Unstructured.Object["spec"])
 	if err != nil {
-		return "", err
+		return nil, []error{fmt.Errorf("cannot marshal PrometheusRule spec: %w", err)}
 	}
-	defer func(renderedFile *os.File) {
-		_ = renderedFile.Close()
-	}(renderedFile)
 
-	_, err = renderedFile.Write(marshalledYaml)
-	if err != nil {
-		return "", err
+	groups := new(ruleGroups)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(groups); err != nil && !stderrors.Is(err, io.EOF) {
+		return nil, []error{err}
 	}
-	_ = renderedFile.Sync()
-	return renderedFile.Name(), nil
-}
 
-func checkRuleFile(path string) error {
-	promtoolComand := exec.Command(promtoolPath, "check", "rules", path)
-	_, err := promtoolComand.Output()
-	return err
+	return groups, groups.validate()
 }
 
-func createPromtoolError(m *module.Module, errMsg string) *errors.LintRuleError {
-	return errors.NewLintRuleError(
-		ID,
-		m.GetName(),
-		m.GetPath(),
-		nil,
-		"Promtool check failed for Helm chart:\n%s",
-		errMsg,
-	)
+func (g *ruleGroups) validate() (errs []error) {
+	names := make(map[string]struct{})
+	for _, group := range g.Groups {
+		if group.Name == "" {
+			errs = append(errs, fmt.Errorf("group name must not be empty"))
+		}
+		if _, ok := names[group.Name]; ok {
+			errs = append(errs, fmt.Errorf("group name %q is repeated in the same object", group.Name))
+		}
+		names[group.Name] = struct{}{}
+
+		if group.Interval != "" {
+			if _, err := model.ParseDuration(group.Interval); err != nil {
+				errs = append(errs, fmt.Errorf("group %q: invalid interval: %w", group.Name, err))
+			}
+		}
+
+		for i := range group.Rules {
+			for _, err := range group.Rules[i].validate() {
+				errs = append(errs, &ruleError{group: group.Name, number: i + 1, name: group.Rules[i].name(), err: err})
+			}
+		}
+	}
+
+	return errs
 }
 
-func PromtoolRuleCheck(m *module.Module, object storage.StoreObject) *errors.LintRuleError {
-	// check promtoolPath exist, if not do not run linter
-	if _, err := os.Stat(promtoolPath); err != nil {
-		return nil
+//nolint:gocyclo // every rule field is checked
+func (r *ruleSpec) validate() (errs []error) {
+	switch {
+	case r.Record != "" && r.Alert != "":
+		errs = append(errs, fmt.Errorf("only one of 'record' and 'alert' must be set"))
+	case r.Record == "" && r.Alert == "":
+		errs = append(errs, fmt.Errorf("one of 'record' or 'alert' must be set"))
 	}
 
-	if object.Unstructured.GetKind() != "PrometheusRule" {
-		return nil
+	if r.Expr == "" {
+		errs = append(errs, fmt.Errorf("field 'expr' must be set in rule"))
+	} else if _, err := parser.ParseExpr(r.Expr); err != nil {
+		errs = append(errs, fmt.Errorf("could not parse expression: %w", err))
 	}
 
-	res, ok := rulesCache.Get(object.Hash)
-	if ok {
-		if !res.success {
-			return createPromtoolError(m, res.errMsg)
+	for _, field := range [][2]string{{"for", r.For}, {"keep_firing_for", r.KeepFiringFor}} {
+		if field[1] == "" {
+			continue
+		}
+		if _, err := model.ParseDuration(field[1]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid field '%s': %w", field[0], err))
+		} else if r.Record != "" {
+			errs = append(errs, fmt.Errorf("invalid field '%s' in recording rule", field[0]))
 		}
-		return nil
 	}
 
-	marshal, err := marshalChartYaml(object)
-	if err != nil {
-		return errors.NewLintRuleError(
-			ID,
-			m.GetName(),
-			m.GetPath(),
-			nil,
-			"Error marshaling Helm chart to yaml",
-		)
+	if r.Record != "" {
+		if len(r.Annotations) > 0 {
+			errs = append(errs, fmt.Errorf("invalid field 'annotations' in recording rule"))
+		}
+		if !model.IsValidMetricName(model.LabelValue(r.Record)) {
+			errs = append(errs, fmt.Errorf("invalid recording rule name: %s", r.Record))
+		}
 	}
 
-	path, err := writeTempRuleFileFromObject(m, marshal)
-	defer os.Remove(path)
+	for _, name := range slices.Sorted(maps.Keys(r.Labels)) {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			errs = append(errs, fmt.Errorf("invalid label name: %s", name))
+		}
+		if !model.LabelValue(r.Labels[name]).IsValid() {
+			errs = append(errs, fmt.Errorf("invalid label value: %s", r.Labels[name]))
+		}
+	}
 
-	if err != nil {
+	for _, name := range slices.Sorted(maps.Keys(r.Annotations)) {
+		if !model.LabelName(name).IsValid() {
+			errs = append(errs, fmt.Errorf("invalid annotation name: %s", name))
+		}
+	}
+
+	if r.Alert == "" {
+		return errs
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.Labels)) {
+		if err := parseTemplate(r.Alert, r.Labels[name]); err != nil {
+			errs = append(errs, fmt.Errorf("label %q: %w", name, err))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Annotations)) {
+		if err := parseTemplate(r.Alert, r.Annotations[name]); err != nil {
+			errs = append(errs, fmt.Errorf("annotation %q: %w", name, err))
+		}
+	}
+
+	return errs
+}
+
+// parseTemplate parses the alert label or annotation template like Prometheus does on rules loading
+func parseTemplate(alert, text string) error {
+	_, err := template.New("__alert_" + alert).Funcs(templateFuncs).Option("missingkey=zero").Parse(templateDefs + text)
+	return err
+}
+
+func ruleLintError(moduleName string, object storage.StoreObject, err error) *errors.LintRuleError {
+	var ruleErr *ruleError
+	if !stderrors.As(err, &ruleErr) {
 		return errors.NewLintRuleError(
 			ID,
-			m.GetName(),
-			m.GetPath(),
+			object.Identity(),
+			moduleName,
 			nil,
-			"Error creating temporary rule file from Helm chart:\n%s",
-			err.Error(),
+			"Invalid Prometheus rules: %s", err,
 		)
 	}
 
-	err = checkRuleFile(path)
-	if err != nil {
-		errorMessage := string(err.(*exec.ExitError).Stderr)
-		rulesCache.Put(object.Hash, checkResult{
-			success: false,
-			errMsg:  errorMessage,
-		})
-		return createPromtoolError(m, errorMessage)
+	name := ruleErr.name
+	if name == "" {
+		name = fmt.Sprintf("#%d", ruleErr.number)
+	}
+
+	return errors.NewLintRuleError(
+		ID,
+		object.Identity(),
+		moduleName,
+		ruleErr.group+"/"+name,
+		"Invalid Prometheus rule %q in group %q: %s", name, ruleErr.group, ruleErr.err,
+	)
+}
+
+// duplicateRules reports rules with the same record or alert name and labels,
+// Prometheus evaluates both and they produce conflicting series.
+func duplicateRules(moduleName string, object storage.StoreObject, groups *ruleGroups) (result errors.LintRuleErrorsList) {
+	seen := make(map[string]string)
+	for _, group := range groups.Groups {
+		for _, rule := range group.Rules {
+			name := rule.name()
+
+			key := ruleKey(name, rule.Labels)
+			firstGroup, ok := seen[key]
+			if !ok {
+				seen[key] = group.Name
+				continue
+			}
+
+			result.Add(errors.NewLintRuleError(
+				ID,
+				object.Identity(),
+				moduleName,
+				group.Name+"/"+name,
+				"Prometheus rule %q in group %q duplicates the rule with the same name and labels in group %q", name, group.Name, firstGroup,
+			))
+		}
+	}
+
+	return result
+}
+
+func ruleKey(name string, labels map[string]string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, label := range slices.Sorted(maps.Keys(labels)) {
+		fmt.Fprintf(&b, "\x00%s=%s", label, labels[label])
 	}
 
-	rulesCache.Put(object.Hash, checkResult{success: true})
-	return nil
+	return b.String()
 }
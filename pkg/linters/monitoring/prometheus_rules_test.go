@@ -0,0 +1,61 @@
+package monitoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/internal/storage"
+)
+
+const prometheusRule = `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: test
+spec:
+  groups:
+    - name: test.general
+      rules:
+        - alert: TestDown
+          expr: up{job="test"} == 0
+          for: 5 minutes
+    - name: test.broken
+      rules:
+        - alert: TestBroken
+          expr: sum(rate(requests_total[5m]) by (pod)
+          annotations:
+            summary: "{{ $labels.pod "
+        - record: test:requests:rate5m
+          expr: sum(rate(requests_total[5m]))
+        - record: test:requests:rate5m
+          expr: sum(rate(requests_total[10m]))
+`
+
+func parseObject(t *testing.T, content string) storage.StoreObject {
+	t.Helper()
+
+	var object unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(content), &object.Object))
+
+	return storage.StoreObject{Unstructured: object}
+}
+
+func TestPrometheusRuleCheck(t *testing.T) {
+	result := PrometheusRuleCheck("test", parseObject(t, prometheusRule))
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, `in duration "5 minutes"`)
+
+	result = PrometheusRuleCheck("test", parseObject(t, strings.Replace(prometheusRule, "5 minutes", "5m", 1)))
+	text = result.ConvertToError().Error()
+	assert.Contains(t, text, `Invalid Prometheus rule "TestBroken" in group "test.broken"`)
+	assert.Contains(t, text, "parse error")
+	assert.Contains(t, text, `annotation "summary"`)
+	assert.Contains(t, text, `Prometheus rule "test:requests:rate5m" in group "test.broken" duplicates the rule`)
+	assert.Contains(t, text, "test.broken/TestBroken")
+	assert.NotContains(t, text, "TestDown")
+}
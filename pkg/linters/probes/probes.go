@@ -36,11 +36,11 @@ func (*Probes) Run(m *module.Module) (result errors.LintRuleErrorsList, err erro
 		var g = pool.New().WithErrors()
 		g.Go(func() error {
 			for _, object := range m.GetStorage() {
-				containers, er := object.GetContainers()
-				if er != nil || containers == nil {
+				template, er := object.PodTemplate()
+				if er != nil || template == nil || template.Spec.Containers == nil {
 					continue
 				}
-				ch <- containerProbes(m.GetName(), object, containers)
+				ch <- containerProbes(m.GetName(), object, template.Spec.Containers)
 			}
 
 			return nil
@@ -0,0 +1,47 @@
+package references
+
+import (
+	"slices"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "references"
+)
+
+// References linter
+type References struct {
+	name, desc string
+	cfg        *config.ReferencesSettings
+}
+
+func New(cfg *config.ReferencesSettings) *References {
+	return &References{
+		name: "references",
+		desc: "Lint references of pod templates to objects",
+		cfg:  cfg,
+	}
+}
+
+func (o *References) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+	if m == nil {
+		return result, err
+	}
+
+	if slices.Contains(o.cfg.SkipModuleChecks, m.GetName()) {
+		return result, err
+	}
+
+	return danglingReferences(m.GetName(), m.GetObjectStore(), slices.Concat(DefaultExternalObjects, o.cfg.ExternalObjects)), nil
+}
+
+func (o *References) Name() string {
+	return o.name
+}
+
+func (o *References) Desc() string {
+	return o.desc
+}
@@ -0,0 +1,73 @@
+package references
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+// DefaultExternalObjects are created by Kubernetes in every cluster or namespace
+var DefaultExternalObjects = []string{
+	"*/ServiceAccount/default",
+	"*/ConfigMap/kube-root-ca.crt",
+	"PriorityClass/system-cluster-critical",
+	"PriorityClass/system-node-critical",
+}
+
+// danglingReferences reports references of pod templates to ServiceAccounts, ConfigMaps, Secrets,
+// PersistentVolumeClaims and PriorityClasses which are neither rendered by the module, nor created from its objects,
+// nor match externalObjects patterns of storage.ResourceIndex.AsString. Optional references are not checked.
+func danglingReferences(moduleName string, store *storage.UnstructuredObjectStore, externalObjects []string) (result errors.LintRuleErrorsList) {
+	if store == nil {
+		return result
+	}
+
+	generated := generatedObjects(store)
+
+	for _, object := range store.ByKind(podTemplateKinds...) {
+		references, err := object.PodReferences()
+		if err != nil {
+			// conversion errors are reported by other linters
+			continue
+		}
+
+		for _, reference := range references {
+			if reference.Optional || store.Exists(reference.ResourceIndex) || reference.MatchesAny(externalObjects) {
+				continue
+			}
+			if _, ok := generated[reference.ResourceIndex]; ok {
+				continue
+			}
+
+			result.Add(errors.NewLintRuleError(
+				ID,
+				object.Identity(),
+				moduleName,
+				reference.AsString(),
+				"Pod template refers to %s %q which is not rendered by the module", reference.Kind, reference.Name,
+			))
+		}
+	}
+
+	return result
+}
+
+// podTemplateKinds run pods, PodTemplate objects are not checked
+var podTemplateKinds = []string{"Pod", "Deployment", "DaemonSet", "StatefulSet", "ReplicaSet", "ReplicationController", "Job", "CronJob"}
+
+// generatedObjects returns objects controllers create from objects of the module:
+// Secrets of cert-manager Certificates named by spec.secretName
+func generatedObjects(store *storage.UnstructuredObjectStore) map[storage.ResourceIndex]struct{} {
+	result := make(map[storage.ResourceIndex]struct{})
+	for _, object := range store.ByKind("Certificate") {
+		secretName, _, _ := unstructured.NestedString(object.Unstructured.Object, "spec", "secretName")
+		if secretName == "" {
+			continue
+		}
+
+		result[storage.ResourceIndex{Kind: "Secret", Name: secretName, Namespace: object.Unstructured.GetNamespace()}] = struct{}{}
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package references
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deckhouse/dmt/internal/module/moduletest"
+)
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: d8-test
+spec:
+  template:
+    spec:
+      serviceAccountName: web
+      priorityClassName: cluster-medium
+      imagePullSecrets:
+        - name: deckhouse-registry
+      volumes:
+        - name: config
+          configMap: {name: web-config}
+        - name: extra
+          configMap: {name: web-extra, optional: true}
+        - name: data
+          persistentVolumeClaim: {claimName: web-data}
+        - name: token
+          projected:
+            sources:
+              - configMap: {name: kube-root-ca.crt}
+        - name: tls
+          secret: {secretName: web-tls}
+      containers:
+        - name: web
+          envFrom:
+            - secretRef: {name: web-env}
+          env:
+            - name: PASSWORD
+              valueFrom:
+                secretKeyRef: {name: web-password, key: password}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+  namespace: d8-test
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: d8-test
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: web-env
+  namespace: d8-test
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: web
+  namespace: d8-test
+spec:
+  secretName: web-tls
+  dnsNames: [web.d8-test.svc]
+  issuerRef: {name: selfsigned, kind: ClusterIssuer}
+`
+
+func TestDanglingReferences(t *testing.T) {
+	store := moduletest.ObjectStore(t, manifests)
+
+	result := danglingReferences("test", store, DefaultExternalObjects)
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, `Pod template refers to PriorityClass "cluster-medium" which is not rendered by the module`)
+	assert.Contains(t, text, `Secret "deckhouse-registry"`)
+	assert.Contains(t, text, `PersistentVolumeClaim "web-data"`)
+	assert.Contains(t, text, `Secret "web-password"`)
+	assert.Contains(t, text, "Value\t- d8-test/Secret/web-password")
+	for _, resolved := range []string{`"web"`, `"web-config"`, `"web-extra"`, `"web-env"`, `"kube-root-ca.crt"`, `"web-tls"`} {
+		assert.NotContains(t, text, resolved)
+	}
+
+	result = danglingReferences("test", store, append([]string{
+		"PriorityClass/cluster-*",
+		"*/Secret/deckhouse-registry",
+		"d8-test/PersistentVolumeClaim/web-data",
+		"d8-test/Secret/web-password",
+	}, DefaultExternalObjects...))
+	assert.NoError(t, result.ConvertToError())
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "repository"
+)
+
+// Repository linter checks objects rendered by different modules
+type Repository struct {
+	name, desc string
+	cfg        *config.RepositorySettings
+}
+
+func New(cfg *config.RepositorySettings) *Repository {
+	return &Repository{
+		name: "repository",
+		desc: "Lint object collisions and references across modules",
+		cfg:  cfg,
+	}
+}
+
+func (o *Repository) RunRepository(store *storage.RepositoryStore) (result errors.LintRuleErrorsList, err error) {
+	if store == nil {
+		return result, err
+	}
+
+	result.Merge(duplicateModuleNames(store))
+	result.Merge(objectCollisions(store, o.cfg.SkipObjects))
+	result.Merge(webhookNameCollisions(store))
+	result.Merge(danglingServiceAccountBindings(store))
+
+	return result, nil
+}
+
+func (o *Repository) Name() string {
+	return o.name
+}
+
+func (o *Repository) Desc() string {
+	return o.desc
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+// duplicateModuleNames reports different modules with the same name.
+// Helm releases are named after modules, so such modules overwrite each other.
+func duplicateModuleNames(store *storage.RepositoryStore) (result errors.LintRuleErrorsList) {
+	for _, moduleName := range store.Modules() {
+		paths := store.ModulePaths(moduleName)
+		if len(paths) < 2 {
+			continue
+		}
+
+		result.Add(errors.NewLintRuleError(
+			ID,
+			moduleName,
+			moduleName,
+			nil,
+			"Module name is used by several modules: %s",
+			strings.Join(paths, ", "),
+		))
+	}
+
+	return result
+}
+
+// objectCollisions reports objects rendered by several modules.
+// Helm can't install an object owned by another release, so only one module may render it.
+func objectCollisions(store *storage.RepositoryStore, skipObjects []string) (result errors.LintRuleErrorsList) {
+	for _, index := range store.Indexes() {
+		if slices.Contains(skipObjects, index.AsString()) {
+			continue
+		}
+
+		modules := moduleNames(store.Get(index))
+		if len(modules) < 2 {
+			continue
+		}
+
+		result.Add(errors.NewLintRuleError(
+			ID,
+			store.Get(index)[0].Identity(),
+			strings.Join(modules, ", "),
+			nil,
+			"Object is rendered by several modules: %s",
+			strings.Join(modules, ", "),
+		))
+	}
+
+	return result
+}
+
+// webhookNameCollisions reports admission webhooks with the same name registered by several modules.
+func webhookNameCollisions(store *storage.RepositoryStore) (result errors.LintRuleErrorsList) {
+	for _, kind := range []string{"ValidatingWebhookConfiguration", "MutatingWebhookConfiguration"} {
+		webhooks := make(map[string][]storage.ModuleObject)
+		var names []string
+
+		for _, object := range store.ByKind(kind) {
+			items, _, _ := unstructured.NestedSlice(object.Unstructured.Object, "webhooks")
+			for _, item := range items {
+				webhook, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := webhook["name"].(string)
+				if name == "" {
+					continue
+				}
+				if _, ok := webhooks[name]; !ok {
+					names = append(names, name)
+				}
+				webhooks[name] = append(webhooks[name], object)
+			}
+		}
+
+		slices.Sort(names)
+		for _, name := range names {
+			modules := moduleNames(webhooks[name])
+			if len(modules) < 2 {
+				continue
+			}
+
+			result.Add(errors.NewLintRuleError(
+				ID,
+				fmt.Sprintf("kind = %s ; webhook = %s", kind, name),
+				strings.Join(modules, ", "),
+				nil,
+				"Webhook name is registered by several modules: %s",
+				strings.Join(modules, ", "),
+			))
+		}
+	}
+
+	return result
+}
+
+// danglingServiceAccountBindings reports bindings to a ServiceAccount in the namespace of another module
+// which is not rendered by that module.
+// Bindings to namespaces of modules not being linted can't be checked.
+func danglingServiceAccountBindings(store *storage.RepositoryStore) (result errors.LintRuleErrorsList) {
+	for _, object := range store.ByKind("RoleBinding", "ClusterRoleBinding") {
+		subjects, _, _ := unstructured.NestedSlice(object.Unstructured.Object, "subjects")
+		for _, item := range subjects {
+			subject, ok := item.(map[string]any)
+			if !ok || subject["kind"] != "ServiceAccount" {
+				continue
+			}
+
+			name, _ := subject["name"].(string)
+			namespace, _ := subject["namespace"].(string)
+			if namespace == "" {
+				namespace = object.Unstructured.GetNamespace()
+			}
+
+			// bindings to the own namespace are checked by the rbac linter
+			owners := store.ModulesByNamespace(namespace)
+			if len(owners) == 0 || slices.Contains(owners, object.Module) {
+				continue
+			}
+
+			index := storage.ResourceIndex{Kind: "ServiceAccount", Name: name, Namespace: namespace}
+			if store.Exists(index) {
+				continue
+			}
+
+			result.Add(errors.NewLintRuleError(
+				ID,
+				object.Identity(),
+				object.Module,
+				index.AsString(),
+				"%s binds to the ServiceAccount %s/%s, which is not rendered by modules of the namespace: %s",
+				object.Unstructured.GetKind(), namespace, name, strings.Join(owners, ", "),
+			))
+		}
+	}
+
+	return result
+}
+
+func moduleNames(objects []storage.ModuleObject) []string {
+	result := make([]string, 0, len(objects))
+	for _, object := range objects {
+		result = append(result, object.Module)
+	}
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/deckhouse/dmt/internal/storage"
+	"github.com/deckhouse/dmt/pkg/config"
+)
+
+func newModuleStore(t *testing.T, manifests string) *storage.UnstructuredObjectStore {
+	t.Helper()
+
+	store := storage.NewUnstructuredObjectStore()
+	for _, manifest := range strings.Split(strings.TrimSpace(manifests), "\n---\n") {
+		var object map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), &object))
+		store.Put("templates/test.yaml", object, []byte(manifest))
+	}
+
+	return store
+}
+
+func TestRepositoryRules(t *testing.T) {
+	store := storage.NewRepositoryStore("v1.30.0")
+	store.Add("first", "modules/first", "d8-first", newModuleStore(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: d8:shared
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: first
+webhooks:
+- name: validate.deckhouse.io
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: first
+  namespace: d8-first
+`))
+	store.Add("second", "modules/second", "d8-second", newModuleStore(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: d8:shared
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: second
+webhooks:
+- name: validate.deckhouse.io
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: d8:second
+subjects:
+- kind: ServiceAccount
+  name: first
+  namespace: d8-first
+- kind: ServiceAccount
+  name: missing
+  namespace: d8-first
+- kind: ServiceAccount
+  name: external
+  namespace: kube-system
+`))
+
+	result, err := New(&config.RepositorySettings{}).RunRepository(store)
+	require.NoError(t, err)
+
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "Object is rendered by several modules: first, second")
+	assert.Contains(t, text, "Webhook name is registered by several modules: first, second")
+	assert.Contains(t, text, "binds to the ServiceAccount d8-first/missing")
+	assert.NotContains(t, text, "ServiceAccount d8-first/first,")
+	assert.NotContains(t, text, "kube-system/external")
+	assert.NotContains(t, text, "Module name is used by several modules")
+
+	result, err = New(&config.RepositorySettings{SkipObjects: []string{"ClusterRole/d8:shared"}}).RunRepository(store)
+	require.NoError(t, err)
+	assert.NotContains(t, result.ConvertToError().Error(), "Object is rendered by several modules")
+}
+
+func TestDuplicateModuleNames(t *testing.T) {
+	store := storage.NewRepositoryStore("v1.30.0")
+	store.Add("first", "modules/first", "d8-first", nil)
+	store.Add("first", "ee/modules/first", "d8-first", nil)
+	store.Add("second", "modules/second", "d8-second", nil)
+
+	assert.Equal(t, []string{"first", "second"}, store.Modules())
+	assert.Equal(t, []string{"first"}, store.ModulesByNamespace("d8-first"))
+
+	result, err := New(&config.RepositorySettings{}).RunRepository(store)
+	require.NoError(t, err)
+	require.Error(t, result.ConvertToError())
+	assert.Contains(t, result.ConvertToError().Error(), "Module name is used by several modules: ee/modules/first, modules/first")
+}
@@ -0,0 +1,92 @@
+package values
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const globalKey = "global"
+
+// undefinedValues reports values read by templates, but not defined by the module or global schema.
+// Global values are checked only if the global schema is available.
+func undefinedValues(m *module.Module, references []reference, moduleSchema, globalSchema *spec.Schema) (result errors.LintRuleErrorsList) {
+	moduleKey := module.ToLowerCamel(m.GetName())
+
+	for _, ref := range references {
+		if len(ref.path) == 0 {
+			continue
+		}
+
+		var undefined []string
+		switch ref.path[0] {
+		case moduleKey:
+			if prefix := undefinedPath(moduleSchema, ref.path[1:]); prefix != nil {
+				undefined = append([]string{moduleKey}, prefix...)
+			}
+		case globalKey:
+			if globalSchema == nil || len(properties(globalSchema)) == 0 {
+				continue
+			}
+			if prefix := undefinedPath(globalSchema, ref.path[1:]); prefix != nil {
+				undefined = append([]string{globalKey}, prefix...)
+			}
+		default:
+			undefined = ref.path[:1]
+		}
+
+		if undefined == nil {
+			continue
+		}
+
+		result.Add(errors.NewLintRuleError(
+			ID,
+			ref.location,
+			m.GetName(),
+			valuesPath(ref.path),
+			"Value %s is not defined in the OpenAPI schema",
+			valuesPath(undefined),
+		))
+	}
+
+	return result
+}
+
+// unusedValues reports module schema properties not read by any template.
+// Values read by helm_lib and skipped paths are not reported.
+func unusedValues(m *module.Module, references []reference, moduleSchema *spec.Schema, skip []string) (result errors.LintRuleErrorsList) {
+	moduleKey := module.ToLowerCamel(m.GetName())
+
+	var moduleReferences []reference
+	for _, ref := range references {
+		switch {
+		case len(ref.path) == 0:
+			// the whole .Values is read
+			moduleReferences = append(moduleReferences, reference{subtree: ref.subtree})
+		case ref.path[0] == moduleKey:
+			moduleReferences = append(moduleReferences, reference{path: ref.path[1:], subtree: ref.subtree})
+		}
+	}
+	for _, path := range skip {
+		moduleReferences = append(moduleReferences, reference{path: strings.Split(path, "."), subtree: true})
+	}
+
+	for _, path := range unusedPaths(moduleSchema, nil, moduleReferences) {
+		result.Add(errors.NewLintRuleError(
+			ID,
+			valuesPath(append([]string{moduleKey}, path...)),
+			m.GetName(),
+			nil,
+			"Value is defined in the OpenAPI schema, but is not read by templates",
+		))
+	}
+
+	return result
+}
+
+func valuesPath(path []string) string {
+	return strings.Join(append([]string{".Values"}, path...), ".")
+}
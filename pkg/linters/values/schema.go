@@ -0,0 +1,88 @@
+package values
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/go-openapi/spec"
+)
+
+// properties returns properties of the schema including properties of allOf, oneOf and anyOf schemas
+func properties(schema *spec.Schema) map[string]spec.Schema {
+	result := maps.Clone(schema.Properties)
+	if result == nil {
+		result = make(map[string]spec.Schema)
+	}
+
+	for _, sub := range slices.Concat(schema.AllOf, schema.OneOf, schema.AnyOf) {
+		for name, prop := range properties(&sub) {
+			if _, ok := result[name]; !ok {
+				result[name] = prop
+			}
+		}
+	}
+
+	return result
+}
+
+// undefinedPath returns the prefix of the path up to the first key not defined by the schema,
+// nil if the path is defined. Free-form objects and array items accept any keys.
+func undefinedPath(schema *spec.Schema, path []string) []string {
+	for i, key := range path {
+		if isFreeForm(schema) {
+			return nil
+		}
+
+		prop, ok := properties(schema)[key]
+		if !ok {
+			return path[:i+1]
+		}
+		schema = &prop
+	}
+
+	return nil
+}
+
+func isFreeForm(schema *spec.Schema) bool {
+	if schema.Type.Contains("array") || len(schema.PatternProperties) > 0 {
+		return true
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Allows {
+		return true
+	}
+
+	return len(properties(schema)) == 0
+}
+
+// unusedPaths returns the topmost schema properties which neither themselves nor their parents
+// or children are read by templates.
+func unusedPaths(schema *spec.Schema, prefix []string, references []reference) [][]string {
+	props := properties(schema)
+
+	var result [][]string
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		path := append(slices.Clone(prefix), name)
+
+		switch {
+		case slices.ContainsFunc(references, func(ref reference) bool {
+			return ref.subtree && hasPrefix(path, ref.path)
+		}):
+			// the value is read as a whole
+		case slices.ContainsFunc(references, func(ref reference) bool {
+			return hasPrefix(ref.path, path)
+		}):
+			prop := props[name]
+			if !isFreeForm(&prop) {
+				result = append(result, unusedPaths(&prop, path, references)...)
+			}
+		default:
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
+
+func hasPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && slices.Equal(path[:len(prefix)], prefix)
+}
@@ -0,0 +1,240 @@
+package values
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// reference is a `.Values` path read by a template
+type reference struct {
+	path []string
+	// subtree is true if the whole value is read, e.g. by toYaml, and false if only its fields are read, e.g. by with
+	subtree bool
+	// location is the template path and line, e.g. module/templates/a.yaml:12
+	location string
+}
+
+// value is what dot or a variable points to while walking a template
+type value struct {
+	// root is true for the top-level template context
+	root bool
+	// known is true if the value is a `.Values` path
+	known  bool
+	values []string
+}
+
+var unknown = value{}
+
+func (v value) isValues() bool {
+	return v.known
+}
+
+// field returns the value of the fields chain, e.g. `.Values.a.b` applied to the root returns the values path a.b
+func (v value) field(idents ...string) value {
+	switch {
+	case len(idents) == 0:
+		return v
+	case v.root:
+		if idents[0] != "Values" {
+			return unknown
+		}
+		return value{known: true, values: slices.Clone(idents[1:])}
+	case v.known:
+		return value{known: true, values: slices.Concat(v.values, idents)}
+	default:
+		return unknown
+	}
+}
+
+// walker collects `.Values` references of a template tree
+type walker struct {
+	tree       *parse.Tree
+	references []reference
+}
+
+// templateReferences returns `.Values` paths read by the chart templates.
+// Dot is assumed to be the top-level context in template and define bodies, helpers are usually included with it.
+// Values read with computed keys are skipped. Templates which can't be parsed are skipped, the render reports them.
+func templateReferences(ch *chart.Chart) []reference {
+	var result []reference
+	for _, t := range ch.Templates {
+		treeSet := make(map[string]*parse.Tree)
+		tree := parse.New(path.Join(ch.Name(), t.Name))
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(string(t.Data), "{{", "}}", treeSet); err != nil {
+			continue
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(treeSet)) {
+			w := &walker{tree: treeSet[name]}
+			w.walk(treeSet[name].Root, value{root: true}, map[string]value{"$": {root: true}})
+			result = append(result, w.references...)
+		}
+	}
+
+	return result
+}
+
+func (w *walker) walk(node parse.Node, dot value, vars map[string]value) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, dot, vars)
+		}
+	case *parse.ActionNode:
+		w.read(n.Pipe, dot, vars)
+	case *parse.TemplateNode:
+		w.read(n.Pipe, dot, vars)
+	case *parse.IfNode:
+		inner := scope(vars)
+		w.read(n.Pipe, dot, inner)
+		w.walk(n.List, dot, inner)
+		w.walk(n.ElseList, dot, scope(vars))
+	case *parse.RangeNode:
+		// items are not tracked, so the whole value is read
+		inner := scope(vars)
+		w.use(n.Pipe, w.pipe(n.Pipe, dot, inner))
+		for _, decl := range n.Pipe.Decl {
+			inner[decl.Ident[0]] = unknown
+		}
+		w.walk(n.List, unknown, inner)
+		w.walk(n.ElseList, dot, scope(vars))
+	case *parse.WithNode:
+		inner := scope(vars)
+		w.walk(n.List, w.with(n.Pipe, dot, inner), inner)
+		w.walk(n.ElseList, dot, scope(vars))
+	}
+}
+
+// scope returns variables of a nested block, variables declared in the block are not visible outside
+func scope(vars map[string]value) map[string]value {
+	return maps.Clone(vars)
+}
+
+// with returns the dot of the with body. The value itself is not read as a whole,
+// so its fields read in the body are checked separately.
+func (w *walker) with(pipe *parse.PipeNode, dot value, vars map[string]value) value {
+	if len(pipe.Decl) == 0 && len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1 {
+		v := w.eval(pipe.Cmds[0].Args[0], dot, vars)
+		if v.isValues() {
+			w.add(pipe, v.values, false)
+			return v
+		}
+	}
+
+	return w.read(pipe, dot, vars)
+}
+
+// read records the value of the pipeline as read, unless it is assigned to a variable
+func (w *walker) read(pipe *parse.PipeNode, dot value, vars map[string]value) value {
+	v := w.pipe(pipe, dot, vars)
+	if len(pipe.Decl) == 0 {
+		w.use(pipe, v)
+	}
+
+	return v
+}
+
+// pipe returns the value of the pipeline and assigns it to declared variables
+func (w *walker) pipe(pipe *parse.PipeNode, dot value, vars map[string]value) value {
+	if pipe == nil {
+		return unknown
+	}
+
+	result := unknown
+	for i, cmd := range pipe.Cmds {
+		// the result of the previous command is passed to the next one as the last argument
+		if i > 0 {
+			w.use(cmd, result)
+		}
+		result = w.command(cmd, dot, vars)
+	}
+
+	for _, decl := range pipe.Decl {
+		vars[decl.Ident[0]] = result
+	}
+
+	return result
+}
+
+// command returns the value of the command, arguments of functions are recorded as read
+func (w *walker) command(cmd *parse.CommandNode, dot value, vars map[string]value) value {
+	if len(cmd.Args) == 1 {
+		return w.eval(cmd.Args[0], dot, vars)
+	}
+
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "index" && len(cmd.Args) > 1 {
+		v := w.eval(cmd.Args[1], dot, vars)
+		for _, arg := range cmd.Args[2:] {
+			key, ok := arg.(*parse.StringNode)
+			if !ok || !v.isValues() {
+				w.use(cmd, v)
+				w.args(cmd.Args[2:], dot, vars)
+				return unknown
+			}
+			v = v.field(key.Text)
+		}
+		return v
+	}
+
+	w.args(cmd.Args, dot, vars)
+
+	return unknown
+}
+
+func (w *walker) args(args []parse.Node, dot value, vars map[string]value) {
+	for _, arg := range args {
+		w.use(arg, w.eval(arg, dot, vars))
+	}
+}
+
+// eval returns the value of the argument, nested pipelines are recorded as read
+func (w *walker) eval(node parse.Node, dot value, vars map[string]value) value {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return dot.field(n.Ident...)
+	case *parse.VariableNode:
+		v, ok := vars[n.Ident[0]]
+		if !ok {
+			return unknown
+		}
+		return v.field(n.Ident[1:]...)
+	case *parse.ChainNode:
+		v := w.eval(n.Node, dot, vars)
+		return v.field(n.Field...)
+	case *parse.PipeNode:
+		return w.pipe(n, dot, scope(vars))
+	default:
+		return unknown
+	}
+}
+
+// use records the value as read as a whole
+func (w *walker) use(node parse.Node, v value) {
+	if v.isValues() {
+		w.add(node, v.values, true)
+	}
+}
+
+func (w *walker) add(node parse.Node, values []string, subtree bool) {
+	location, _ := w.tree.ErrorContext(node)
+	// ErrorContext returns path:line:column
+	if i := strings.LastIndex(location, ":"); i > 0 {
+		if _, err := strconv.Atoi(location[i+1:]); err == nil {
+			location = location[:i]
+		}
+	}
+
+	w.references = append(w.references, reference{path: values, subtree: subtree, location: location})
+}
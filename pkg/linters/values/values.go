@@ -0,0 +1,63 @@
+package values
+
+import (
+	"slices"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+	"github.com/deckhouse/dmt/pkg/errors"
+)
+
+const (
+	ID = "values"
+)
+
+// Values linter
+type Values struct {
+	name, desc string
+	cfg        *config.ValuesSettings
+}
+
+func New(cfg *config.ValuesSettings) *Values {
+	return &Values{
+		name: "values",
+		desc: "Lint values read by templates against OpenAPI schemas",
+		cfg:  cfg,
+	}
+}
+
+func (o *Values) Run(m *module.Module) (result errors.LintRuleErrorsList, err error) {
+	if m == nil || m.GetChart() == nil {
+		return result, err
+	}
+
+	if slices.Contains(o.cfg.SkipModuleChecks, m.GetName()) {
+		return result, err
+	}
+
+	validator := m.GetValuesValidator()
+	if validator == nil {
+		return result, nil
+	}
+
+	moduleSchema := validator.ModuleSchemaStorages[m.GetName()].Schemas["values"]
+	if moduleSchema == nil {
+		return result, nil
+	}
+	globalSchema := validator.GlobalSchemaStorage.Schemas["values"]
+
+	references := templateReferences(m.GetChart())
+
+	result.Merge(undefinedValues(m, references, moduleSchema, globalSchema))
+	result.Merge(unusedValues(m, references, moduleSchema, slices.Concat(o.cfg.LibraryValues, o.cfg.SkipUnusedValues[m.GetName()])))
+
+	return result, nil
+}
+
+func (o *Values) Name() string {
+	return o.name
+}
+
+func (o *Values) Desc() string {
+	return o.desc
+}
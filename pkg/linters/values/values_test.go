@@ -0,0 +1,99 @@
+package values
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/deckhouse/dmt/internal/module"
+	"github.com/deckhouse/dmt/pkg/config"
+)
+
+func TestValues(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		module.ModuleYamlFilename: "name: test-values\nnamespace: d8-test-values\n",
+		"openapi/config-values.yaml": `
+type: object
+properties:
+  logLevel:
+    type: string
+    default: Info
+  unused:
+    type: string
+  https:
+    type: object
+    properties:
+      mode:
+        type: string
+  resources:
+    type: object
+    default: {}
+    properties:
+      requests:
+        type: object
+        additionalProperties: true
+      limits:
+        type: object
+        additionalProperties: true
+`,
+		"openapi/values.yaml": `
+x-extend:
+  schema: config-values.yaml
+type: object
+properties:
+  internal:
+    type: object
+    default: {}
+    properties:
+      certificate:
+        type: string
+      servers:
+        type: array
+        items:
+          type: string
+`,
+		"templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: d8-test-values
+data:
+  level: {{ .Values.testValues.logLevel | quote }}
+  {{- with .Values.testValues.resources }}
+  requests: {{ .requests | toJson | quote }}
+  typo: {{ .limit | quote }}
+  {{- end }}
+  {{- range $server := $.Values.testValues.internal.servers }}
+  server: {{ $server }}
+  {{- end }}
+  other: {{ index .Values "otherModule" | quote }}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	m, err := module.NewModule(dir, nil)
+	require.NoError(t, err)
+
+	result, err := New(&config.ValuesSettings{LibraryValues: config.DefaultLibraryValues}).Run(m)
+	require.NoError(t, err)
+
+	text := result.ConvertToError().Error()
+	assert.Contains(t, text, "Value .Values.testValues.resources.limit is not defined in the OpenAPI schema")
+	assert.Contains(t, text, "test-values/templates/cm.yaml:10")
+	assert.Contains(t, text, "Value .Values.otherModule is not defined in the OpenAPI schema")
+	assert.Contains(t, text, "Object\t- .Values.testValues.unused")
+	assert.Contains(t, text, "Object\t- .Values.testValues.resources.limits")
+	assert.Contains(t, text, "Object\t- .Values.testValues.internal.certificate")
+	assert.NotContains(t, text, "Object\t- .Values.testValues.logLevel")
+	assert.NotContains(t, text, "Object\t- .Values.testValues.https")
+	assert.NotContains(t, text, "Object\t- .Values.testValues.internal.servers")
+	assert.NotContains(t, text, "Object\t- .Values.testValues.resources.requests")
+}